@@ -0,0 +1,39 @@
+// Command protocol-schema writes the JSON Schema for the current WebSocket
+// protocol version to <dir>/v<N>.schema.json. Run it via go generate in
+// internal/game. It refuses to change the schema of a version that already
+// has one: bump game.ProtocolVersion instead.
+package main
+
+import (
+	"bytes"
+	"drawl/internal/game"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", "protocol", "directory for the schema files")
+	flag.Parse()
+
+	schema, err := game.ProtocolSchema()
+	if err != nil {
+		log.Fatal(err)
+	}
+	path := filepath.Join(*dir, fmt.Sprintf("v%d.schema.json", game.ProtocolVersion))
+	if old, err := os.ReadFile(path); err == nil {
+		if bytes.Equal(old, schema) {
+			return
+		}
+		log.Fatalf("%s already exists with a different schema: bump game.ProtocolVersion", path)
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(path, schema, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s", path)
+}
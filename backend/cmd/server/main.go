@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 func main() {
@@ -31,7 +32,17 @@ func main() {
 
 	h := hub.New()
 	registry := ws.NewClientRegistry()
+	switch v := os.Getenv("WS_SESSIONS"); v {
+	case "", "latest":
+	case "mirror":
+		registry.Policy = ws.Mirror
+	default:
+		log.Printf("ignoring WS_SESSIONS=%q: want latest or mirror", v)
+	}
 	wsHandler := ws.NewHandler(h, registry)
+	wsHandler.Heartbeat.Interval = durationEnv("WS_PING_INTERVAL", wsHandler.Heartbeat.Interval)
+	wsHandler.Heartbeat.Timeout = durationEnv("WS_PING_TIMEOUT", wsHandler.Heartbeat.Timeout)
+	wsHandler.Heartbeat.IdleAfter = durationEnv("WS_IDLE_AFTER", wsHandler.Heartbeat.IdleAfter)
 	handlers := &api.Handlers{Hub: h, Registry: registry, AI: aiHandler, GamePassword: gamePassword}
 	router := api.NewRouter(h, registry, wsHandler, handlers)
 
@@ -40,3 +51,18 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// durationEnv reads a duration such as "30s" from the environment, falling
+// back to def if it's unset or invalid.
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("ignoring %s=%q: want a positive duration like 30s", name, v)
+		return def
+	}
+	return d
+}
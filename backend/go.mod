@@ -7,4 +7,9 @@ require (
 	nhooyr.io/websocket v1.8.17
 )
 
-require golang.org/x/image v0.36.0
+require (
+	github.com/fxamacker/cbor/v2 v2.9.2
+	golang.org/x/image v0.36.0
+)
+
+require github.com/x448/float16 v0.8.4 // indirect
@@ -1,6 +1,10 @@
 package ai
 
-import "drawl/internal/game"
+import (
+	"context"
+
+	"drawl/internal/game"
+)
 
 // Handler implements game.AIHandler using OpenAI APIs.
 // When APIKey is empty, it acts as a no-op (returns placeholders).
@@ -17,10 +21,10 @@ func NewHandler(apiKey string) game.AIHandler {
 
 type noopHandler struct{}
 
-func (h *noopHandler) GuessDrawing(imageDataURL string) (string, error) {
+func (h *noopHandler) GuessDrawing(ctx context.Context, imageDataURL string) (string, error) {
 	return "???", nil
 }
 
-func (h *noopHandler) DrawPrompt(prompt string) (string, error) {
+func (h *noopHandler) DrawPrompt(ctx context.Context, prompt string) (string, error) {
 	return "", nil
 }
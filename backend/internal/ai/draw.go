@@ -2,6 +2,7 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -10,7 +11,7 @@ import (
 	"net/http"
 )
 
-func (h *Handler) DrawPrompt(prompt string) (string, error) {
+func (h *Handler) DrawPrompt(ctx context.Context, prompt string) (string, error) {
 	if prompt == "" || prompt == "???" {
 		return "", nil
 	}
@@ -27,7 +28,7 @@ func (h *Handler) DrawPrompt(prompt string) (string, error) {
 	data, _ := json.Marshal(body)
 	log.Printf("[ai/draw] POST images/generations model=gpt-image-1-mini prompt=%q", prompt)
 
-	req, _ := http.NewRequest("POST", "https://api.openai.com/v1/images/generations", bytes.NewReader(data))
+	req, _ := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/images/generations", bytes.NewReader(data))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+h.APIKey)
 
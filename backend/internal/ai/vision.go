@@ -2,6 +2,7 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,7 +10,7 @@ import (
 	"net/http"
 )
 
-func (h *Handler) GuessDrawing(imageDataURL string) (string, error) {
+func (h *Handler) GuessDrawing(ctx context.Context, imageDataURL string) (string, error) {
 	if imageDataURL == "" {
 		return "???", nil
 	}
@@ -43,7 +44,7 @@ func (h *Handler) GuessDrawing(imageDataURL string) (string, error) {
 	data, _ := json.Marshal(body)
 	log.Printf("[ai/vision] POST responses model=gpt-5-mini image_size=%d", len(resized))
 
-	req, _ := http.NewRequest("POST", "https://api.openai.com/v1/responses", bytes.NewReader(data))
+	req, _ := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/responses", bytes.NewReader(data))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+h.APIKey)
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"drawl/internal/game"
+	"drawl/internal/httputil"
 	"drawl/internal/hub"
 	"drawl/internal/ws"
 	"encoding/json"
@@ -31,17 +32,17 @@ type createGameResponse struct {
 func (h *Handlers) CreateGame(w http.ResponseWriter, r *http.Request) {
 	var req createGameRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpError(w, "invalid request", http.StatusBadRequest)
+		httputil.Error(w, "invalid request", http.StatusBadRequest)
 		return
 	}
 	if h.GamePassword != "" && req.Password != h.GamePassword {
-		httpError(w, "wrong password", http.StatusUnauthorized)
+		httputil.Error(w, "wrong password", http.StatusUnauthorized)
 		return
 	}
 
 	req.PlayerName = strings.TrimSpace(req.PlayerName)
 	if req.PlayerName == "" {
-		httpError(w, "name required", http.StatusBadRequest)
+		httputil.Error(w, "name required", http.StatusBadRequest)
 		return
 	}
 	if len(req.PlayerName) > 20 {
@@ -83,13 +84,13 @@ type joinGameResponse struct {
 func (h *Handlers) JoinGame(w http.ResponseWriter, r *http.Request) {
 	var req joinGameRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httpError(w, "invalid request", http.StatusBadRequest)
+		httputil.Error(w, "invalid request", http.StatusBadRequest)
 		return
 	}
 	req.PlayerName = strings.TrimSpace(req.PlayerName)
 	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
 	if req.PlayerName == "" || req.Code == "" {
-		httpError(w, "name and code required", http.StatusBadRequest)
+		httputil.Error(w, "name and code required", http.StatusBadRequest)
 		return
 	}
 	if len(req.PlayerName) > 20 {
@@ -98,7 +99,7 @@ func (h *Handlers) JoinGame(w http.ResponseWriter, r *http.Request) {
 
 	g := h.Hub.GetGame(req.Code)
 	if g == nil {
-		httpError(w, "game not found", http.StatusNotFound)
+		httputil.Error(w, "game not found", http.StatusNotFound)
 		return
 	}
 
@@ -113,8 +114,54 @@ func (h *Handlers) JoinGame(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func httpError(w http.ResponseWriter, msg string, code int) {
+type gameHistoryResponse struct {
+	Code   string             `json:"code"`
+	Games  []*game.GameRecord `json:"games"`
+	Wins   map[string]int     `json:"wins"`
+	Scores map[string]int     `json:"scores"`
+}
+
+func (h *Handlers) GameHistory(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(strings.TrimSpace(r.PathValue("code")))
+	g := h.Hub.GetGame(code)
+	if g == nil {
+		httputil.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+
+	games, wins, scores := g.History()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gameHistoryResponse{
+		Code:   code,
+		Games:  games,
+		Wins:   wins,
+		Scores: scores,
+	})
+}
+
+type myTurnsResponse struct {
+	Turns []game.PendingTurn `json:"turns"`
+}
+
+// MyTurns lists the async turns waiting on the caller, identified by their
+// player token (Authorization: Bearer <token>, or ?token=).
+func (h *Handlers) MyTurns(w http.ResponseWriter, r *http.Request) {
+	token := httputil.PlayerToken(r)
+	if token == "" {
+		httputil.Error(w, "token required", http.StatusUnauthorized)
+		return
+	}
+	g := h.Hub.GetGame(strings.ToUpper(strings.TrimSpace(r.PathValue("code"))))
+	if g == nil {
+		httputil.Error(w, "game not found", http.StatusNotFound)
+		return
+	}
+	turns, ok := g.MyTurns(token)
+	if !ok {
+		httputil.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	json.NewEncoder(w).Encode(myTurnsResponse{Turns: turns})
 }
@@ -149,3 +149,92 @@ func TestCreateGame_InvalidJSON(t *testing.T) {
 		t.Errorf("status = %d, want 400", w.Code)
 	}
 }
+
+func TestGameHistory_Empty(t *testing.T) {
+	h := newTestHandlers("")
+	createReq := httptest.NewRequest("POST", "/api/games", bytes.NewBufferString(`{"playerName":"Alice"}`))
+	createW := httptest.NewRecorder()
+	h.CreateGame(createW, createReq)
+	var createResp createGameResponse
+	json.NewDecoder(createW.Body).Decode(&createResp)
+
+	req := httptest.NewRequest("GET", "/api/games/"+createResp.Code+"/history", nil)
+	req.SetPathValue("code", createResp.Code)
+	w := httptest.NewRecorder()
+
+	h.GameHistory(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp gameHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if resp.Code != createResp.Code {
+		t.Errorf("Code = %q, want %q", resp.Code, createResp.Code)
+	}
+	if len(resp.Games) != 0 {
+		t.Errorf("Games len = %d, want 0", len(resp.Games))
+	}
+}
+
+func TestGameHistory_GameNotFound(t *testing.T) {
+	h := newTestHandlers("")
+	req := httptest.NewRequest("GET", "/api/games/ZZZZZ/history", nil)
+	req.SetPathValue("code", "ZZZZZ")
+	w := httptest.NewRecorder()
+
+	h.GameHistory(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestMyTurns_RequiresToken(t *testing.T) {
+	h := newTestHandlers("")
+	createReq := httptest.NewRequest("POST", "/api/games", bytes.NewBufferString(`{"playerName":"Alice"}`))
+	createW := httptest.NewRecorder()
+	h.CreateGame(createW, createReq)
+	var createResp createGameResponse
+	json.NewDecoder(createW.Body).Decode(&createResp)
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		want   int
+	}{
+		{"missing token", "", "", http.StatusUnauthorized},
+		{"bad token", "", "?token=nope", http.StatusUnauthorized},
+		{"query token", "", "?token=" + createResp.Token, http.StatusOK},
+		{"bearer token", "Bearer " + createResp.Token, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/games/"+createResp.Code+"/my-turns"+tt.query, nil)
+			req.SetPathValue("code", createResp.Code)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			h.MyTurns(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var resp myTurnsResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if resp.Turns == nil || len(resp.Turns) != 0 {
+				t.Errorf("Turns = %v, want empty list in the lobby", resp.Turns)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"drawl/internal/httputil"
 	"net"
 	"net/http"
 	"sync"
@@ -72,7 +73,7 @@ func RateLimitMiddleware(next http.Handler) http.Handler {
 	rl := newRateLimiter(5, 30, time.Minute)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !rl.allow(clientIP(r)) {
-			httpError(w, "too many attempts, try again later", http.StatusTooManyRequests)
+			httputil.Error(w, "too many attempts, try again later", http.StatusTooManyRequests)
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -13,7 +13,11 @@ func NewRouter(h *hub.Hub, registry *ws.ClientRegistry, wsHandler *ws.Handler, h
 
 	mux.Handle("POST /api/games", RateLimitMiddleware(http.HandlerFunc(handlers.CreateGame)))
 	mux.HandleFunc("POST /api/games/join", handlers.JoinGame)
+	mux.HandleFunc("GET /api/games/{code}/history", handlers.GameHistory)
+	mux.HandleFunc("GET /api/games/{code}/my-turns", handlers.MyTurns)
 	mux.Handle("/ws", wsHandler)
+	mux.HandleFunc("GET /api/games/{code}/events", wsHandler.ServeEvents)
+	mux.HandleFunc("POST /api/games/{code}/messages", wsHandler.ServeMessages)
 
 	// Serve static frontend if the directory exists
 	staticDir := "./static"
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// Async and pipeline modes: every chain advances on its own. As soon as a
+// turn is submitted the chain moves on to its next holder.
+//
+// In async (play-by-post) mode the turn is offered straight away with a
+// deadline of hours rather than seconds; players may have several turns
+// waiting and pick them up whenever they open the app.
+//
+// In pipeline mode each player works on one turn at a time against the
+// usual turn timer, with later turns queued behind it (see pipeline.go).
+
+// PendingTurn is a turn waiting on a player in async or pipeline mode.
+type PendingTurn struct {
+	ChainIdx int       `json:"chainIdx"`
+	Round    int       `json:"round"`
+	TurnType TurnType  `json:"turnType"`
+	Prompt   string    `json:"prompt"`
+	Deadline time.Time `json:"deadline"`
+	TimeLeft int       `json:"timeLeft,omitempty"` // seconds, while paused and there's no deadline
+}
+
+// startChains offers the first turn on every chain.
+func (g *Game) startChains() {
+	log.Printf("[game %s] %s game started, %d rounds", g.State.Code, g.State.Settings.Mode, g.State.TotalRounds)
+	for ci := range g.State.Chains {
+		g.offerChainTurn(ci)
+	}
+}
+
+// offerChainTurn hands a chain's pending turn to its holder: straight away
+// in async mode, or onto their queue in pipeline mode.
+func (g *Game) offerChainTurn(chainIdx int) {
+	info, ok := g.State.ChainTurn(chainIdx)
+	if !ok {
+		return
+	}
+	if g.State.Settings.Mode == ModePipeline {
+		g.enqueueTurn(info)
+		return
+	}
+	deadline := time.Duration(g.State.Settings.asyncDeadline()) * time.Second
+	g.startChainTimer(info, deadline)
+
+	p := g.State.FindPlayer(info.PlayerID)
+	if p == nil {
+		return
+	}
+	if p.Type == AIPlayer {
+		go g.handleAITurn(info)
+		return
+	}
+	g.send(info.PlayerID, OutgoingMessage{Type: MsgTurnStart, Data: g.chainTurnData(info)})
+}
+
+// chainTurnData builds a turn_start payload for a per-chain turn. While
+// paused the turn has no deadline, only the time it had left.
+func (g *Game) chainTurnData(info TurnInfo) TurnStartData {
+	chainIdx := info.ChainIdx
+	t := TurnStartData{
+		Round:       info.Round,
+		TotalRounds: g.State.TotalRounds,
+		TurnType:    info.TurnType,
+		Prompt:      info.Prompt,
+		ChainIdx:    &chainIdx,
+		Pending:     len(g.State.PendingTurns(info.PlayerID)),
+	}
+	if left, ok := g.pausedChainLeft(info.ChainIdx); ok {
+		t.TimeLimit = left
+		return t
+	}
+	deadline := g.chainDeadlines[info.ChainIdx]
+	t.TimeLimit = int(time.Until(deadline).Seconds())
+	t.Deadline = &deadline
+	return t
+}
+
+// pausedChainLeft returns the seconds a chain's turn timer had left when
+// the game was paused, and whether it's paused at all.
+func (g *Game) pausedChainLeft(chainIdx int) (int, bool) {
+	if g.paused == nil {
+		return 0, false
+	}
+	left := g.paused.chains[chainIdx]
+	return int((left + time.Second - 1) / time.Second), true
+}
+
+// startChainTimer auto-submits a blank entry if the turn isn't taken
+// before the deadline.
+func (g *Game) startChainTimer(info TurnInfo, d time.Duration) {
+	g.stopChainTimer(info.ChainIdx)
+	if g.paused != nil {
+		g.paused.chains[info.ChainIdx] = d // armed on resume
+		return
+	}
+	g.chainDeadlines[info.ChainIdx] = time.Now().Add(d)
+
+	var t *time.Timer
+	t = time.AfterFunc(d, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.chainTimers[info.ChainIdx] != t {
+			return // stopped or replaced while waiting for the lock
+		}
+		log.Printf("[game %s] chain %d round %d timed out", g.State.Code, info.ChainIdx, info.Round+1)
+		content := ""
+		if info.TurnType == TurnGuess {
+			content = "???"
+		}
+		g.submitChainTurn(info.PlayerID, info.ChainIdx, info.Round, info.TurnType, content)
+	})
+	g.chainTimers[info.ChainIdx] = t
+}
+
+func (g *Game) stopChainTimer(chainIdx int) {
+	if t := g.chainTimers[chainIdx]; t != nil {
+		t.Stop()
+	}
+	delete(g.chainTimers, chainIdx)
+	delete(g.chainDeadlines, chainIdx)
+	if g.paused != nil {
+		delete(g.paused.chains, chainIdx)
+	}
+}
+
+func (g *Game) stopChainTimers() {
+	for ci := range g.chainTimers {
+		g.stopChainTimer(ci)
+	}
+	g.queues = make(map[string][]int)
+}
+
+// handleChainSubmit records a human's submission on one of their pending
+// turns. chainIdx picks the turn in async mode; nil means the first pending
+// turn of the right type. Pipeline players can only submit their current
+// turn.
+func (g *Game) handleChainSubmit(playerID string, chainIdx *int, turnType TurnType, content string) {
+	if g.State.Phase != PhasePlaying {
+		g.sendError(playerID, "game is not in progress")
+		return
+	}
+	candidates := g.State.PendingTurns(playerID)
+	if g.State.Settings.Mode == ModePipeline {
+		candidates = g.currentTurn(playerID)
+	}
+	var turn *TurnInfo
+	for _, info := range candidates {
+		if info.TurnType != turnType {
+			continue
+		}
+		if chainIdx == nil || *chainIdx == info.ChainIdx {
+			turn = &info
+			break
+		}
+	}
+	if turn == nil {
+		g.sendError(playerID, "no matching turn to submit")
+		return
+	}
+
+	g.submitChainTurn(playerID, turn.ChainIdx, turn.Round, turnType, content)
+
+	if g.State.Settings.Mode == ModePipeline {
+		// The next queued turn, if any, has already been served.
+		if len(g.queues[playerID]) == 0 {
+			g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: WaitingData{}})
+		}
+		return
+	}
+	pending := g.State.PendingTurns(playerID)
+	if len(pending) > 0 && g.State.Phase == PhasePlaying {
+		g.send(playerID, OutgoingMessage{Type: MsgTurnStart, Data: g.chainTurnData(pending[0])})
+		return
+	}
+	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: WaitingData{}})
+}
+
+// submitChainTurn records an entry on a chain and moves the chain on to
+// its next holder. Returns false if the turn no longer matches.
+func (g *Game) submitChainTurn(playerID string, chainIdx, round int, turnType TurnType, content string) bool {
+	if !g.State.SubmitToChain(playerID, chainIdx, round, turnType, content) {
+		return false
+	}
+	g.stopChainTimer(chainIdx)
+	g.dequeueTurn(playerID, chainIdx)
+
+	if g.State.SyncRound() {
+		g.broadcast(OutgoingMessage{Type: MsgRoundComplete, Data: RoundCompleteData{
+			Round: g.State.Round - 1,
+		}})
+	}
+	if g.State.AllChainsComplete() {
+		log.Printf("[game %s] all chains complete", g.State.Code)
+		g.stopChainTimers()
+		g.enterReveal()
+		return true
+	}
+	g.offerChainTurn(chainIdx)
+	return true
+}
+
+// MyTurns returns the async turns waiting on the player with the given
+// token. ok is false if no player has that token.
+func (g *Game) MyTurns(token string) (turns []PendingTurn, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.State.FindPlayerByToken(token)
+	if p == nil {
+		return nil, false
+	}
+	turns = []PendingTurn{}
+	if g.State.Phase != PhasePlaying || g.State.Settings.Mode == ModeSync {
+		return turns, true
+	}
+	if g.State.Settings.Mode == ModePipeline {
+		for _, ci := range g.queues[p.ID] {
+			info, _ := g.State.ChainTurn(ci)
+			turns = append(turns, g.pendingTurn(info)) // no deadline until the turn is served
+		}
+		return turns, true
+	}
+	for _, info := range g.State.PendingTurns(p.ID) {
+		turns = append(turns, g.pendingTurn(info))
+	}
+	return turns, true
+}
+
+// pendingTurn describes a turn waiting on a player, for MyTurns.
+func (g *Game) pendingTurn(info TurnInfo) PendingTurn {
+	t := PendingTurn{
+		ChainIdx: info.ChainIdx,
+		Round:    info.Round,
+		TurnType: info.TurnType,
+		Prompt:   info.Prompt,
+		Deadline: g.chainDeadlines[info.ChainIdx],
+	}
+	if left, ok := g.pausedChainLeft(info.ChainIdx); ok && left > 0 {
+		t.TimeLeft = left
+	}
+	return t
+}
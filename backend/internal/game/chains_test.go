@@ -0,0 +1,118 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAsync_ChainAdvancesWithoutWaitingForRound(t *testing.T) {
+	g, rec := newModeGame(t, ModeAsync, 3)
+	p0, p1 := g.State.Players[0], g.State.Players[1]
+
+	if rec.lastSent(p0.ID, MsgTurnStart) == nil {
+		t.Fatal("expected turn_start for every player")
+	}
+
+	chainIdx := 0
+	g.HandleMessage(p0.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d0", ChainIdx: &chainIdx}))
+
+	if r := g.State.ChainRound(0); r != 1 {
+		t.Fatalf("chain 0 round = %d, want 1", r)
+	}
+	next, _ := g.State.ChainTurn(0)
+	if next.PlayerID != p1.ID {
+		t.Fatalf("chain 0 next holder = %s, want %s", next.PlayerID, p1.ID)
+	}
+
+	// p1 can guess on chain 0 before drawing on their own chain.
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "dog", ChainIdx: &chainIdx}))
+	if r := g.State.ChainRound(0); r != 2 {
+		t.Errorf("chain 0 round = %d, want 2", r)
+	}
+	if r := g.State.ChainRound(1); r != 0 {
+		t.Errorf("chain 1 round = %d, want 0", r)
+	}
+	if g.State.Round != 0 {
+		t.Errorf("Round = %d, want 0 while chains 1 and 2 lag", g.State.Round)
+	}
+}
+
+func TestAsync_GameEndsWhenAllChainsComplete(t *testing.T) {
+	g, rec := newModeGame(t, ModeAsync, 2)
+
+	for g.State.Phase == PhasePlaying {
+		for _, p := range g.State.Players {
+			for _, info := range g.State.PendingTurns(p.ID) {
+				if info.TurnType == TurnDraw {
+					g.HandleMessage(p.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d"}))
+				} else {
+					g.HandleMessage(p.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "g"}))
+				}
+			}
+		}
+	}
+
+	if g.State.Phase != PhaseReveal {
+		t.Fatalf("Phase = %d, want PhaseReveal", g.State.Phase)
+	}
+	if rec.lastBroadcast(MsgGameOver) == nil {
+		t.Error("expected game_over broadcast")
+	}
+	if len(g.chainTimers) != 0 {
+		t.Errorf("%d chain timers still running", len(g.chainTimers))
+	}
+}
+
+func TestAsync_DisconnectKeepsSeat(t *testing.T) {
+	g, _ := newModeGame(t, ModeAsync, 2)
+	p1 := g.State.Players[1]
+
+	g.HandleDisconnect(p1.ID)
+
+	if g.State.FindPlayer(p1.ID) == nil {
+		t.Error("async player should keep their seat after disconnecting")
+	}
+	if r := g.State.ChainRound(1); r != 0 {
+		t.Errorf("chain 1 round = %d, want 0 (no blank auto-submit)", r)
+	}
+}
+
+func TestMyTurns(t *testing.T) {
+	g, _ := newModeGame(t, ModeAsync, 2)
+	p0 := g.State.Players[0]
+
+	turns, ok := g.MyTurns(p0.Token)
+	if !ok {
+		t.Fatal("MyTurns should accept a valid token")
+	}
+	if len(turns) != 1 || turns[0].ChainIdx != 0 || turns[0].TurnType != TurnDraw {
+		t.Fatalf("turns = %+v, want one draw on chain 0", turns)
+	}
+	if turns[0].Deadline.IsZero() {
+		t.Error("pending turn should have a deadline")
+	}
+	if _, ok := g.MyTurns("bad-token"); ok {
+		t.Error("MyTurns should reject an unknown token")
+	}
+}
+
+func TestAsync_DeadlineAutoSubmits(t *testing.T) {
+	g, _ := newModeGame(t, ModeAsync, 2)
+
+	g.mu.Lock()
+	info, _ := g.State.ChainTurn(0)
+	g.startChainTimer(info, 0)
+	g.mu.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		r := g.State.ChainRound(0)
+		g.mu.Unlock()
+		if r == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expired turn should be auto-submitted")
+}
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Per-player turn deadlines and hurry-up nudges for lockstep rounds.
+//
+// Players can be given their own turn time; the shared round countdown runs
+// to the latest deadline and earlier ones are enforced per player. Once
+// most of the table has submitted and only a couple of players remain, the
+// round countdown is cut short so the stragglers know to finish up.
+
+// TurnTimeFor returns a player's turn time in seconds.
+func (gs *GameState) TurnTimeFor(playerID string) int {
+	if t := gs.Settings.PlayerTurnTimes[playerID]; t > 0 {
+		return t
+	}
+	return gs.TurnTime
+}
+
+// startPlayerDeadlines sets up per-player deadlines for the round that just
+// started, stretching the round countdown to the latest of them. Once it's
+// stretched, players on the default turn time keep the round's original
+// deadline as their own.
+func (g *Game) startPlayerDeadlines() {
+	base := g.deadline
+	latest := base
+	for _, p := range g.State.Players {
+		if g.State.TurnTimeFor(p.ID) == g.State.TurnTime {
+			continue
+		}
+		d := time.Now().Add(time.Duration(g.State.TurnTimeFor(p.ID)) * time.Second)
+		g.playerDeadlines[p.ID] = d
+		if d.After(latest) {
+			latest = d
+		}
+	}
+	if latest.After(base) {
+		for _, p := range g.State.Players {
+			if _, ok := g.playerDeadlines[p.ID]; !ok {
+				g.playerDeadlines[p.ID] = base
+			}
+		}
+		g.setDeadline(latest)
+	}
+	for id, d := range g.playerDeadlines {
+		if d.Before(g.deadline) {
+			g.startPlayerTimer(id, d)
+		}
+	}
+}
+
+// startPlayerTimer auto-submits one player when their own deadline passes.
+func (g *Game) startPlayerTimer(playerID string, d time.Time) {
+	var t *time.Timer
+	t = time.AfterFunc(time.Until(d), func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.playerTimers[playerID] != t {
+			return // stopped or replaced while waiting for the lock
+		}
+		delete(g.playerTimers, playerID)
+		p := g.State.FindPlayer(playerID)
+		if p == nil || g.submitted[playerID] {
+			return
+		}
+		log.Printf("[game %s] player %s ran out of time", g.State.Code, playerID)
+		g.forceSubmit(p)
+		g.checkRoundComplete()
+	})
+	g.playerTimers[playerID] = t
+}
+
+func (g *Game) stopPlayerTimers() {
+	for id, t := range g.playerTimers {
+		t.Stop()
+		delete(g.playerTimers, id)
+	}
+	g.playerDeadlines = make(map[string]time.Time)
+}
+
+// playerSecondsLeft returns how long a player has left on their turn.
+func (g *Game) playerSecondsLeft(playerID string) int {
+	if g.State.Settings.Mode != ModeSync {
+		for _, info := range g.State.PendingTurns(playerID) {
+			if d, ok := g.chainDeadlines[info.ChainIdx]; ok {
+				return secondsUntil(d)
+			}
+		}
+		return 0
+	}
+	d, ok := g.playerDeadlines[playerID]
+	if !ok || d.After(g.deadline) {
+		return g.secondsLeft()
+	}
+	return secondsUntil(d)
+}
+
+// tickData builds a countdown tick payload. Turn ticks include per-player
+// remaining time when anyone has their own deadline.
+func (g *Game) tickData(tickType string, remaining int) TickData {
+	data := TickData{Remaining: remaining}
+	if tickType != MsgTurnTick {
+		return data
+	}
+	data.Hurry = g.hurried
+	if len(g.playerDeadlines) > 0 {
+		data.Players = make(map[string]int)
+		for id := range g.playerDeadlines {
+			if !g.submitted[id] {
+				data.Players[id] = g.playerSecondsLeft(id)
+			}
+		}
+	}
+	return data
+}
+
+// stragglers returns the players still holding up play: those who haven't
+// submitted this round, or who have turns waiting in async/pipeline mode.
+func (g *Game) stragglers() []*Player {
+	var out []*Player
+	for _, p := range g.State.Players {
+		switch g.State.Settings.Mode {
+		case ModeSync:
+			if !g.submitted[p.ID] {
+				out = append(out, p)
+			}
+		case ModePipeline:
+			if len(g.currentTurn(p.ID)) > 0 {
+				out = append(out, p)
+			}
+		default:
+			if len(g.State.PendingTurns(p.ID)) > 0 {
+				out = append(out, p)
+			}
+		}
+	}
+	return out
+}
+
+// checkHurry shortens the round countdown once all but a few players have
+// submitted.
+func (g *Game) checkHurry() {
+	s := g.State.Settings
+	if s.Mode != ModeSync || s.HurrySeconds <= 0 || g.hurried || g.timer == nil {
+		return
+	}
+	left := g.stragglers()
+	done := len(g.State.Players) - len(left)
+	if len(left) == 0 || len(left) > s.HurryWhenLeft || done <= len(left) {
+		return
+	}
+	d := time.Now().Add(time.Duration(s.HurrySeconds) * time.Second)
+	if !d.Before(g.deadline) {
+		return
+	}
+
+	log.Printf("[game %s] %d player(s) left, shortening countdown to %ds", g.State.Code, len(left), s.HurrySeconds)
+	g.hurried = true
+	g.setDeadline(d)
+	// Anyone whose own deadline is now later is covered by the round timer.
+	for id, pd := range g.playerDeadlines {
+		if !pd.Before(d) {
+			if t := g.playerTimers[id]; t != nil {
+				t.Stop()
+				delete(g.playerTimers, id)
+			}
+			g.playerDeadlines[id] = d
+		}
+	}
+
+	g.broadcast(OutgoingMessage{Type: MsgTurnTick, Data: g.tickData(MsgTurnTick, g.secondsLeft())})
+	for _, p := range left {
+		if p.Type == HumanPlayer {
+			g.send(p.ID, OutgoingMessage{Type: MsgHurryUp, Data: HurryUpData{
+				Remaining: g.playerSecondsLeft(p.ID),
+				Reason:    "everyone_else_done",
+			}})
+		}
+	}
+}
+
+type nudgeData struct {
+	PlayerID string `json:"playerId,omitempty"` // empty nudges every straggler
+}
+
+func (g *Game) handleNudge(playerID string, data json.RawMessage) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can nudge")
+		return
+	}
+	if g.State.Phase != PhasePlaying {
+		g.sendError(playerID, "game is not in progress")
+		return
+	}
+	var d nudgeData
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &d); err != nil {
+			g.sendError(playerID, "invalid data")
+			return
+		}
+	}
+
+	nudged := 0
+	for _, p := range g.stragglers() {
+		if p.Type != HumanPlayer || (d.PlayerID != "" && p.ID != d.PlayerID) {
+			continue
+		}
+		g.send(p.ID, OutgoingMessage{Type: MsgHurryUp, Data: HurryUpData{
+			Remaining: g.playerSecondsLeft(p.ID),
+			Reason:    "nudge",
+		}})
+		nudged++
+	}
+	if nudged == 0 {
+		g.sendError(playerID, "nobody to nudge")
+	}
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHurry_ShortensCountdownForLastPlayers(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 4)
+	players := g.State.Players
+
+	g.HandleMessage(players[0].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+	g.HandleMessage(players[1].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "b"}))
+	if g.hurried {
+		t.Fatal("should not hurry while half the table is still drawing")
+	}
+
+	g.HandleMessage(players[2].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "c"}))
+	if !g.hurried {
+		t.Fatal("expected countdown to be shortened with one player left")
+	}
+	if left := g.secondsLeft(); left > g.State.Settings.HurrySeconds {
+		t.Errorf("secondsLeft = %d, want <= %d", left, g.State.Settings.HurrySeconds)
+	}
+	if rec.lastSent(players[3].ID, MsgHurryUp) == nil {
+		t.Error("expected hurry_up for the last player")
+	}
+	if rec.lastSent(players[0].ID, MsgHurryUp) != nil {
+		t.Error("players who submitted should not be hurried")
+	}
+	tick := rec.lastBroadcast(MsgTurnTick)
+	if tick == nil || !tick.Data.(TickData).Hurry {
+		t.Errorf("expected turn_tick with hurry flag, got %+v", tick)
+	}
+}
+
+func TestHurry_Disabled(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 3, func(s *GameState) { s.Settings.HurrySeconds = 0 })
+
+	g.HandleMessage(g.State.Players[0].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+	g.HandleMessage(g.State.Players[1].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "b"}))
+	if g.hurried {
+		t.Error("hurry should be disabled when HurrySeconds is 0")
+	}
+}
+
+func TestNudge_TargetsStragglers(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	host := g.State.HostID
+	players := g.State.Players
+
+	g.HandleMessage(players[1].ID, IncomingMessage{Type: MsgNudge})
+	if rec.lastSent(players[1].ID, MsgError) == nil {
+		t.Error("expected error for non-host nudge")
+	}
+
+	g.HandleMessage(players[1].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+	g.HandleMessage(host, IncomingMessage{Type: MsgNudge})
+
+	if rec.lastSent(players[1].ID, MsgHurryUp) != nil {
+		t.Error("player who submitted should not be nudged")
+	}
+	for _, p := range []*Player{players[0], players[2]} {
+		m := rec.lastSent(p.ID, MsgHurryUp)
+		if m == nil {
+			t.Fatalf("expected hurry_up for %s", p.Name)
+		}
+		if reason := m.Data.(HurryUpData).Reason; reason != "nudge" {
+			t.Errorf("reason = %v, want nudge", reason)
+		}
+	}
+}
+
+func TestNudge_SinglePlayer(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	target := g.State.Players[2].ID
+
+	g.HandleMessage(g.State.HostID, msg(t, MsgNudge, nudgeData{PlayerID: target}))
+	if rec.lastSent(target, MsgHurryUp) == nil {
+		t.Error("expected hurry_up for target")
+	}
+	if rec.lastSent(g.State.Players[1].ID, MsgHurryUp) != nil {
+		t.Error("only the target should be nudged")
+	}
+}
+
+func TestPlayerTurnTimes(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3, func(s *GameState) {
+		s.Settings.PlayerTurnTimes = map[string]int{
+			s.Players[1].ID: 120,
+			s.Players[2].ID: 20,
+		}
+	})
+	players := g.State.Players
+
+	want := map[string]int{players[0].ID: g.State.TurnTime, players[1].ID: 120, players[2].ID: 20}
+	for id, limit := range want {
+		m := rec.lastSent(id, MsgTurnStart)
+		if m == nil {
+			t.Fatalf("no turn_start for %s", id)
+		}
+		if got := m.Data.(TurnStartData).TimeLimit; got != limit {
+			t.Errorf("timeLimit for %s = %v, want %d", id, got, limit)
+		}
+	}
+
+	// The round runs to the longest deadline; shorter ones get their own timer.
+	if left := time.Until(g.deadline); left < 110*time.Second {
+		t.Errorf("round deadline in %v, want ~120s", left)
+	}
+	if g.playerTimers[players[2].ID] == nil {
+		t.Error("expected a timer for the player with a shorter deadline")
+	}
+	if g.playerTimers[players[1].ID] != nil {
+		t.Error("the longest deadline is covered by the round timer")
+	}
+	if got := g.playerSecondsLeft(players[2].ID); got > 20 {
+		t.Errorf("playerSecondsLeft = %d, want <= 20", got)
+	}
+	// Players on the default time keep it rather than the stretched round's.
+	if got := g.playerSecondsLeft(players[0].ID); got > g.State.TurnTime {
+		t.Errorf("playerSecondsLeft for the default player = %d, want <= %d", got, g.State.TurnTime)
+	}
+
+	short, _ := newModeGame(t, ModeSync, 2, func(s *GameState) {
+		s.TurnTime = 1
+		s.Settings.PlayerTurnTimes = map[string]int{s.Players[1].ID: 120}
+	})
+	players = short.State.Players
+	time.Sleep(1500 * time.Millisecond)
+	short.mu.Lock()
+	defer short.mu.Unlock()
+	if !short.submitted[players[0].ID] {
+		t.Error("the default player should be force-submitted at the turn time")
+	}
+	if short.submitted[players[1].ID] {
+		t.Error("the player with more time should still be working")
+	}
+}
+
+func TestUpdateSettings_PlayerTurnTimes(t *testing.T) {
+	g, rec := newTestGame(2)
+	host := g.State.HostID
+	other := g.State.Players[1].ID
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]interface{}{
+		"playerTurnTimes": map[string]int{other: MinPlayerTurnTime - 1},
+	}))
+	if rec.lastSent(host, MsgError) == nil {
+		t.Error("expected error for out-of-range turn time")
+	}
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]interface{}{
+		"playerTurnTimes": map[string]int{other: 90},
+	}))
+	if got := g.State.TurnTimeFor(other); got != 90 {
+		t.Errorf("TurnTimeFor = %d, want 90", got)
+	}
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]interface{}{
+		"playerTurnTimes": map[string]int{other: 0},
+	}))
+	if got := g.State.TurnTimeFor(other); got != g.State.TurnTime {
+		t.Errorf("TurnTimeFor = %d, want default %d after clearing", got, g.State.TurnTime)
+	}
+}
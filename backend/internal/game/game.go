@@ -1,9 +1,9 @@
 package game
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"log"
 	"sync"
 	"time"
@@ -20,8 +20,17 @@ const (
 	MsgKickPlayer    = "kick_player"
 
 	MsgSubmitVotes = "submit_votes"
+	MsgEndVoting   = "end_voting"
 	MsgPlayAgain   = "play_again"
 
+	MsgUpdateSettings = "update_settings"
+	MsgAssignTeam     = "assign_team"
+	MsgNudge          = "nudge"
+	MsgPauseGame      = "pause_game"
+	MsgResumeGame     = "resume_game"
+	MsgAbortGame      = "abort_game"
+	MsgResync         = "resync"
+
 	// Server -> Client
 	MsgGameState     = "game_state"
 	MsgPlayerJoined  = "player_joined"
@@ -29,13 +38,24 @@ const (
 	MsgGameStarted   = "game_started"
 	MsgTurnStart     = "turn_start"
 	MsgTurnTick      = "turn_tick"
+	MsgVoteTick      = "vote_tick"
 	MsgWaiting       = "waiting"
 	MsgRoundComplete = "round_complete"
 	MsgGameOver      = "game_over"
-	MsgAIError       = "ai_error"
 	MsgError         = "error"
 	MsgScoreUpdate   = "score_update"
 	MsgReturnToLobby = "return_to_lobby"
+
+	MsgSessionSummary  = "session_summary"
+	MsgSettingsUpdated = "settings_updated"
+	MsgTeamsUpdated    = "teams_updated"
+	MsgHurryUp         = "hurry_up"
+	MsgGamePaused      = "game_paused"
+	MsgGameResumed     = "game_resumed"
+	MsgPlayerAway      = "player_away"
+	MsgSeatTakenOver   = "seat_taken_over"
+	MsgSeatReturned    = "seat_returned"
+	MsgPresence        = "presence"
 )
 
 type IncomingMessage struct {
@@ -43,8 +63,13 @@ type IncomingMessage struct {
 	Data json.RawMessage `json:"data"`
 }
 
+// OutgoingMessage is a server message. Seq numbers the game's broadcasts
+// in order; a message sent to one player, or a countdown tick, carries the
+// Seq of the last broadcast, so a client that sees Seq skip ahead has
+// missed one.
 type OutgoingMessage struct {
 	Type string      `json:"type"`
+	Seq  uint64      `json:"seq"`
 	Data interface{} `json:"data"`
 }
 
@@ -52,8 +77,8 @@ type SendFunc func(playerID string, msg OutgoingMessage)
 type BroadcastFunc func(msg OutgoingMessage)
 
 type AIHandler interface {
-	GuessDrawing(imageDataURL string) (string, error)
-	DrawPrompt(prompt string) (string, error)
+	GuessDrawing(ctx context.Context, imageDataURL string) (string, error)
+	DrawPrompt(ctx context.Context, prompt string) (string, error)
 }
 
 type Game struct {
@@ -62,19 +87,60 @@ type Game struct {
 	send      SendFunc
 	broadcast BroadcastFunc
 	ai        AIHandler
+	aiCtx      context.Context // cancelled when the game is abandoned
+	aiCancel   context.CancelFunc
 	timer      *time.Timer
+	deadline   time.Time       // when timer fires
+	onExpire   func()          // run by timer, under mu
 	tickCancel chan struct{}   // closed to stop the tick goroutine
 	submitted  map[string]bool // tracks submissions per round
+	seq        uint64          // Seq of the last broadcast
+
+	playerTimers    map[string]*time.Timer // player → timer for a deadline earlier than the round's
+	playerDeadlines map[string]time.Time   // player → own deadline this round, if overridden
+	hurried         bool                   // round countdown already shortened this round
+
+	paused *pauseState // non-nil while the host has the game paused
+
+	graceTimers map[string]*time.Timer // disconnected playerID → timer until a bot takes their seat
+	takenOver   map[string]*Player     // playerID → departed player whose seat a bot holds
+
+	chainTimers    map[int]*time.Timer // chain → deadline timer for its pending turn
+	chainDeadlines map[int]time.Time   // chain → when its pending turn expires
+	queues         map[string][]int    // pipeline mode: playerID → chains waiting, current first
 }
 
 func NewGame(code string, host *Player, send SendFunc, broadcast BroadcastFunc, ai AIHandler) *Game {
-	return &Game{
+	g := &Game{
 		State:     NewGameState(code, host),
-		send:      send,
-		broadcast: broadcast,
 		ai:        ai,
 		submitted: make(map[string]bool),
+
+		playerTimers:    make(map[string]*time.Timer),
+		playerDeadlines: make(map[string]time.Time),
+		graceTimers:     make(map[string]*time.Timer),
+		takenOver:       make(map[string]*Player),
+
+		chainTimers:    make(map[int]*time.Timer),
+		chainDeadlines: make(map[int]time.Time),
+		queues:         make(map[string][]int),
+	}
+	g.aiCtx, g.aiCancel = context.WithCancel(context.Background())
+
+	// Every message goes out under g.mu, so stamping here numbers them in
+	// the order they were sent.
+	g.send = func(playerID string, msg OutgoingMessage) {
+		msg.Seq = g.seq
+		send(playerID, msg)
+	}
+	g.broadcast = func(msg OutgoingMessage) {
+		if !Supersedable(msg.Type) {
+			g.seq++
+		}
+		msg.Seq = g.seq
+		broadcast(msg)
 	}
+	return g
 }
 
 func (g *Game) HandleMessage(playerID string, msg IncomingMessage) {
@@ -94,8 +160,31 @@ func (g *Game) HandleMessage(playerID string, msg IncomingMessage) {
 		g.handleKickPlayer(playerID, msg.Data)
 	case MsgSubmitVotes:
 		g.handleSubmitVotes(playerID, msg.Data)
+	case MsgEndVoting:
+		g.handleEndVoting(playerID)
 	case MsgPlayAgain:
 		g.handlePlayAgain(playerID)
+	case MsgUpdateSettings:
+		g.handleUpdateSettings(playerID, msg.Data)
+	case MsgAssignTeam:
+		g.handleAssignTeam(playerID, msg.Data)
+	case MsgNudge:
+		g.handleNudge(playerID, msg.Data)
+	case MsgPauseGame:
+		g.handlePauseGame(playerID)
+	case MsgResumeGame:
+		g.handleResumeGame(playerID)
+	case MsgAbortGame:
+		g.handleAbortGame(playerID, msg.Data)
+	case MsgResync:
+		// The client saw a gap in Seq; the snapshot replaces what it missed.
+		g.sendGameState(playerID)
+	default:
+		g.send(playerID, OutgoingMessage{Type: MsgError, Data: ErrorData{
+			Message: "unknown message type",
+			Code:    ErrCodeUnknownType,
+			Type:    msg.Type,
+		}})
 	}
 }
 
@@ -103,19 +192,18 @@ func (g *Game) HandleJoin(player *Player) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	if g.State.Phase != PhaseLobby {
-		g.send(player.ID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "game already started"}})
+	// Spectators take a seat next game, so they count towards the limit.
+	if g.State.PlayerCount()+len(g.State.Spectators) >= 8 {
+		g.sendError(player.ID, "game is full")
 		return
 	}
-	if g.State.PlayerCount() >= 8 {
-		g.send(player.ID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "game is full"}})
+	if g.State.Phase != PhaseLobby {
+		g.joinAsSpectator(player)
 		return
 	}
 
 	g.State.AddPlayer(player)
-	g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: map[string]interface{}{
-		"player": player,
-	}})
+	g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: PlayerJoinedData{Player: player}})
 	g.sendGameState(player.ID)
 }
 
@@ -125,85 +213,81 @@ func (g *Game) HandleDisconnect(playerID string) {
 
 	p := g.State.FindPlayer(playerID)
 	if p == nil {
+		if g.State.RemoveSpectator(playerID) {
+			g.broadcast(OutgoingMessage{Type: MsgPlayerLeft, Data: PlayerLeftData{
+				PlayerID: playerID,
+				HostID:   g.State.HostID,
+			}})
+		}
 		return
 	}
 
-	if g.State.Phase == PhasePlaying && !g.submitted[playerID] {
-		// Auto-submit blank
-		chainIdx, turnType := g.State.GetAssignment(p.Index)
-		if turnType == TurnDraw {
-			g.State.Chains[chainIdx].Entries = append(g.State.Chains[chainIdx].Entries, ChainEntry{
-				PlayerID: playerID, Type: TurnDraw, Drawing: "",
-			})
-		} else {
-			g.State.Chains[chainIdx].Entries = append(g.State.Chains[chainIdx].Entries, ChainEntry{
-				PlayerID: playerID, Type: TurnGuess, Guess: "???",
-			})
-		}
-		g.submitted[playerID] = true
-		g.checkRoundComplete()
+	// Async players come and go; their seat waits for them to reconnect.
+	if g.State.Settings.Mode == ModeAsync && g.State.Phase != PhaseLobby {
+		g.setPresence(playerID, PresenceDisconnected)
+		return
 	}
-
-	if g.State.Phase == PhaseReveal && !g.State.VotesSubmitted[playerID] {
-		g.State.VotesSubmitted[playerID] = true
-		g.State.Votes[playerID] = &PlayerVote{}
-		g.checkAllVotesIn()
+	if g.State.Settings.BotTakeover && g.State.Phase == PhasePlaying {
+		g.setPresence(playerID, PresenceDisconnected)
+		g.startGrace(p)
+		return
 	}
-
-	g.State.RemovePlayer(playerID)
-	g.broadcast(OutgoingMessage{Type: MsgPlayerLeft, Data: map[string]interface{}{
-		"playerId": playerID,
-		"hostId":   g.State.HostID,
-	}})
+	g.removePlayer(p)
 }
 
 func (g *Game) sendGameState(playerID string) {
-	g.send(playerID, OutgoingMessage{Type: MsgGameState, Data: map[string]interface{}{
-		"code":        g.State.Code,
-		"phase":       g.State.Phase,
-		"players":     g.State.Players,
-		"round":       g.State.Round,
-		"totalRounds": g.State.TotalRounds,
-		"hostId":      g.State.HostID,
-		"scores":      g.State.Scores,
-	}})
+	g.send(playerID, OutgoingMessage{Type: MsgGameState, Data: g.snapshot(playerID)})
 }
 
 func (g *Game) handleAddAI(playerID string) {
 	if playerID != g.State.HostID {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "only host can add AI"}})
+		g.sendError(playerID, "only host can add AI")
 		return
 	}
 	if g.State.PlayerCount() >= 8 {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "game is full"}})
+		g.sendError(playerID, "game is full")
 		return
 	}
 	ai := NewAIPlayer()
 	g.State.AddPlayer(ai)
-	g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: map[string]interface{}{
-		"player": ai,
-	}})
+	g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: PlayerJoinedData{Player: ai}})
 }
 
 func (g *Game) handleStartGame(playerID string) {
 	if playerID != g.State.HostID {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "only host can start"}})
+		g.sendError(playerID, "only host can start")
 		return
 	}
 	if g.State.PlayerCount() < 2 {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "need at least 2 players"}})
+		g.sendError(playerID, "need at least 2 players")
+		return
+	}
+	if g.State.Settings.TeamMode && !g.State.TeamsReady() {
+		g.sendError(playerID, "every player needs a team, with at least 2 teams")
 		return
 	}
 	log.Printf("[game %s] starting with %d players", g.State.Code, g.State.PlayerCount())
+	if g.State.Settings.TeamMode && g.State.Settings.CrossTeamHandoff {
+		g.State.SeatByTeam()
+	}
 	g.State.Phase = PhasePlaying
 	g.State.InitChains()
 	g.submitted = make(map[string]bool)
 
-	g.broadcast(OutgoingMessage{Type: MsgGameStarted, Data: nil})
+	g.broadcast(OutgoingMessage{Type: MsgGameStarted, Data: GameStartedData{}})
+	if g.State.Settings.Mode != ModeSync {
+		g.startChains()
+		return
+	}
 	g.startTurn()
 }
 
 func (g *Game) startTurn() {
+	if g.paused != nil {
+		// Paused between rounds: resume starts the turn.
+		g.paused.turnPending = true
+		return
+	}
 	turnType := "draw"
 	if g.State.Round%2 == 1 {
 		turnType = "guess"
@@ -219,23 +303,28 @@ func (g *Game) startTurn() {
 			continue
 		}
 
-		turnData := map[string]interface{}{
-			"round":      g.State.Round,
-			"totalRounds": g.State.TotalRounds,
-			"turnType":   info.TurnType,
-			"prompt":     info.Prompt,
-			"timeLimit":  g.State.TurnTime,
-		}
-
 		if p.Type == AIPlayer {
 			go g.handleAITurn(info)
 		} else {
-			g.send(info.PlayerID, OutgoingMessage{Type: MsgTurnStart, Data: turnData})
+			g.send(info.PlayerID, OutgoingMessage{Type: MsgTurnStart, Data: g.turnData(info, g.State.TurnTimeFor(info.PlayerID))})
 		}
 	}
 
 	// Start turn timer
+	g.hurried = false
 	g.startTimer()
+	g.startPlayerDeadlines()
+}
+
+// turnData builds a turn_start payload for a lockstep turn.
+func (g *Game) turnData(info TurnInfo, timeLimit int) TurnStartData {
+	return TurnStartData{
+		Round:       g.State.Round,
+		TotalRounds: g.State.TotalRounds,
+		TurnType:    info.TurnType,
+		Prompt:      info.Prompt,
+		TimeLimit:   timeLimit,
+	}
 }
 
 func (g *Game) stopTimer() {
@@ -247,108 +336,208 @@ func (g *Game) stopTimer() {
 		close(g.tickCancel)
 		g.tickCancel = nil
 	}
+	g.stopPlayerTimers()
 }
 
+// startTimer starts the turn countdown; on expiry anyone who hasn't
+// submitted gets a blank entry.
 func (g *Game) startTimer() {
-	g.stopTimer()
-
-	g.timer = time.AfterFunc(time.Duration(g.State.TurnTime)*time.Second, func() {
-		g.mu.Lock()
-		defer g.mu.Unlock()
+	g.startCountdown(g.State.TurnTime, PhasePlaying, MsgTurnTick, func() {
 		g.forceSubmitAll()
 		g.checkRoundComplete()
 	})
+}
+
+// startVoteTimer starts the reveal-phase countdown; on expiry anyone who
+// hasn't voted gets an empty vote.
+func (g *Game) startVoteTimer() {
+	tickType := MsgVoteTick
+	if g.State.Settings.Mode == ModeAsync {
+		tickType = "" // no per-second ticks over hours
+	}
+	g.startCountdown(g.voteTime(), PhaseReveal, tickType, func() {
+		g.forceSubmitVotes()
+		g.checkAllVotesIn()
+	})
+}
+
+// voteTime returns how many seconds players get to vote.
+func (g *Game) voteTime() int {
+	if g.State.Settings.Mode == ModeAsync {
+		return g.State.Settings.asyncDeadline()
+	}
+	return g.State.VoteTime
+}
+
+// startCountdown runs onExpire (under g.mu) after the given number of
+// seconds and broadcasts tickType every second while still in phase; an
+// empty tickType skips the ticks. The deadline can be moved with
+// setDeadline. Must be called with g.mu held.
+func (g *Game) startCountdown(seconds int, phase GamePhase, tickType string, onExpire func()) {
+	g.stopTimer()
+	g.onExpire = onExpire
+	g.setDeadline(time.Now().Add(time.Duration(seconds) * time.Second))
+	g.startTicks(phase, tickType)
+}
+
+// startTicks broadcasts tickType every second while still in phase, until
+// the countdown runs out or tickCancel is closed.
+func (g *Game) startTicks(phase GamePhase, tickType string) {
+	if tickType == "" {
+		return
+	}
 
 	// Tick every second — exits when tickCancel is closed
 	done := make(chan struct{})
 	g.tickCancel = done
 	go func() {
-		remaining := g.State.TurnTime
-		for remaining > 0 {
+		for {
 			select {
 			case <-done:
 				return
 			case <-time.After(1 * time.Second):
 			}
-			remaining--
 			g.mu.Lock()
-			if g.State.Phase != PhasePlaying {
+			if g.State.Phase != phase {
 				g.mu.Unlock()
 				return
 			}
-			g.broadcast(OutgoingMessage{Type: MsgTurnTick, Data: map[string]int{"remaining": remaining}})
+			remaining := g.secondsLeft()
+			g.broadcast(OutgoingMessage{Type: tickType, Data: g.tickData(tickType, remaining)})
 			g.mu.Unlock()
+			if remaining <= 0 {
+				return
+			}
 		}
 	}()
 }
 
+// setDeadline (re)arms the running countdown to expire at d.
+func (g *Game) setDeadline(d time.Time) {
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	g.deadline = d
+
+	var t *time.Timer
+	t = time.AfterFunc(time.Until(d), func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.timer != t {
+			return // stopped or replaced while waiting for the lock
+		}
+		g.timer = nil
+		g.onExpire()
+	})
+	g.timer = t
+}
+
+// secondsLeft returns the whole seconds remaining on the countdown.
+func (g *Game) secondsLeft() int {
+	return secondsUntil(g.deadline)
+}
+
+// secondsUntil returns the whole seconds until d, rounded up.
+func secondsUntil(d time.Time) int {
+	left := time.Until(d)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + time.Second - 1) / time.Second)
+}
+
 func (g *Game) forceSubmitAll() {
 	for _, p := range g.State.Players {
-		if g.submitted[p.ID] {
-			continue
-		}
-		chainIdx, turnType := g.State.GetAssignment(p.Index)
-		if turnType == TurnDraw {
-			g.State.Chains[chainIdx].Entries = append(g.State.Chains[chainIdx].Entries, ChainEntry{
-				PlayerID: p.ID, Type: TurnDraw, Drawing: "",
-			})
-		} else {
-			g.State.Chains[chainIdx].Entries = append(g.State.Chains[chainIdx].Entries, ChainEntry{
-				PlayerID: p.ID, Type: TurnGuess, Guess: "???",
-			})
+		if !g.submitted[p.ID] {
+			g.forceSubmit(p)
 		}
-		g.submitted[p.ID] = true
 	}
 }
 
+// forceSubmit records a blank entry for a player who ran out of time.
+func (g *Game) forceSubmit(p *Player) {
+	chainIdx, turnType := g.State.GetAssignment(p.Index)
+	if turnType == TurnDraw {
+		g.State.Chains[chainIdx].AddEntry(ChainEntry{
+			PlayerID: p.ID, Type: TurnDraw, Drawing: "",
+		})
+	} else {
+		g.State.Chains[chainIdx].AddEntry(ChainEntry{
+			PlayerID: p.ID, Type: TurnGuess, Guess: "???",
+		})
+	}
+	g.submitted[p.ID] = true
+}
+
 func (g *Game) handleAITurn(info TurnInfo) {
 	g.mu.Lock()
 	if g.ai == nil {
 		// No AI handler, submit placeholder
 		if info.TurnType == TurnDraw {
-			g.State.SubmitDrawing(info.PlayerID, "")
+			g.submitAITurn(info, "")
 		} else {
-			g.State.SubmitGuess(info.PlayerID, "???")
+			g.submitAITurn(info, "???")
 		}
-		g.submitted[info.PlayerID] = true
-		g.checkRoundComplete()
 		g.mu.Unlock()
 		return
 	}
 	ai := g.ai
-	g.mu.Unlock()
-
-	player := g.State.FindPlayer(info.PlayerID)
+	ctx := g.aiCtx
 	playerName := "unknown"
-	if player != nil {
+	if player := g.State.FindPlayer(info.PlayerID); player != nil {
 		playerName = player.Name
 	}
+	g.mu.Unlock()
 
 	var result string
+	var err error
 	if info.TurnType == TurnGuess {
 		log.Printf("[game] AI %q guessing drawing (round %d, chain %d, prompt_size=%d)",
-			playerName, g.State.Round, info.ChainIdx, len(info.Prompt))
-		var err error
-		result, err = ai.GuessDrawing(info.Prompt)
-		if err != nil {
-			log.Printf("[game] AI %q guess failed, using original prompt: %v", playerName, err)
-			// Fall back to the chain's original word
-			result = g.State.Chains[info.ChainIdx].OriginalWord
-		}
+			playerName, info.Round, info.ChainIdx, len(info.Prompt))
+		result, err = ai.GuessDrawing(ctx, info.Prompt)
 	} else {
 		log.Printf("[game] AI %q drawing prompt=%q (round %d, chain %d)",
-			playerName, info.Prompt, g.State.Round, info.ChainIdx)
-		var err error
-		result, err = ai.DrawPrompt(info.Prompt)
-		if err != nil {
-			log.Printf("[game] AI %q draw failed, using fallback: %v", playerName, err)
-			// Fall back to the most recent drawing in the chain, or a placeholder
-			result = aiFallbackDrawing(g.State.Chains[info.ChainIdx])
-		}
+			playerName, info.Prompt, info.Round, info.ChainIdx)
+		result, err = ai.DrawPrompt(ctx, info.Prompt)
 	}
 
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	if ctx.Err() != nil {
+		return // game was aborted while waiting
+	}
+	if err != nil && info.TurnType == TurnGuess {
+		log.Printf("[game] AI %q guess failed, using original prompt: %v", playerName, err)
+		// Fall back to the chain's original word
+		result = g.State.Chains[info.ChainIdx].OriginalWord
+	} else if err != nil {
+		log.Printf("[game] AI %q draw failed, using fallback: %v", playerName, err)
+		// Fall back to the most recent drawing in the chain, or a placeholder
+		result = aiFallbackDrawing(g.State.Chains[info.ChainIdx])
+	}
+	g.submitAITurn(info, result)
+}
+
+// submitAITurn records an AI player's result, unless the turn was already
+// filled in (e.g. by the timer) while the AI was thinking.
+func (g *Game) submitAITurn(info TurnInfo, result string) {
+	if g.State.Phase != PhasePlaying {
+		return
+	}
+	if g.paused != nil {
+		g.paused.ai = append(g.paused.ai, heldAITurn{info, result})
+		return
+	}
+	if g.State.Settings.Mode != ModeSync {
+		g.submitChainTurn(info.PlayerID, info.ChainIdx, info.Round, info.TurnType, result)
+		return
+	}
+	if g.State.FindPlayer(info.PlayerID) == nil {
+		return // stand-in handed the seat back
+	}
+	if g.submitted[info.PlayerID] || g.State.Round != info.Round {
+		return
+	}
 	if info.TurnType == TurnDraw {
 		g.State.SubmitDrawing(info.PlayerID, result)
 	} else {
@@ -382,87 +571,97 @@ func aiFallbackDrawing(chain *Chain) string {
 	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg))
 }
 
-const maxDrawingBytes = 5 * 1024 * 1024 // 5MB max for drawing data URLs
+// MaxDrawingBytes caps the size of a drawing's data URL.
+const MaxDrawingBytes = 5 * 1024 * 1024
 
 type submitDrawingData struct {
-	Drawing string `json:"drawing"`
+	Drawing  string `json:"drawing"`
+	ChainIdx *int   `json:"chainIdx"` // async mode: which pending turn; defaults to the first
 }
 
 func (g *Game) handleSubmitDrawing(playerID string, data json.RawMessage) {
 	if g.submitted[playerID] {
 		return
 	}
+	if g.State.Paused {
+		g.sendError(playerID, "game is paused")
+		return
+	}
 	var d submitDrawingData
 	if err := json.Unmarshal(data, &d); err != nil {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "invalid data"}})
+		g.sendError(playerID, "invalid data")
+		return
+	}
+	if len(d.Drawing) > MaxDrawingBytes {
+		g.sendError(playerID, "drawing too large")
 		return
 	}
-	if len(d.Drawing) > maxDrawingBytes {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "drawing too large"}})
+	if g.State.Settings.Mode != ModeSync {
+		g.handleChainSubmit(playerID, d.ChainIdx, TurnDraw, d.Drawing)
 		return
 	}
 	if !g.State.SubmitDrawing(playerID, d.Drawing) {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "cannot submit drawing now"}})
+		g.sendError(playerID, "cannot submit drawing now")
 		return
 	}
 	g.submitted[playerID] = true
-	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: nil})
+	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: WaitingData{}})
 	g.checkRoundComplete()
 }
 
 type submitGuessData struct {
-	Guess string `json:"guess"`
+	Guess    string `json:"guess"`
+	ChainIdx *int   `json:"chainIdx"` // async mode: which pending turn; defaults to the first
 }
 
 func (g *Game) handleSubmitGuess(playerID string, data json.RawMessage) {
 	if g.submitted[playerID] {
 		return
 	}
+	if g.State.Paused {
+		g.sendError(playerID, "game is paused")
+		return
+	}
 	var d submitGuessData
 	if err := json.Unmarshal(data, &d); err != nil {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "invalid data"}})
+		g.sendError(playerID, "invalid data")
+		return
+	}
+	if g.State.Settings.Mode != ModeSync {
+		g.handleChainSubmit(playerID, d.ChainIdx, TurnGuess, d.Guess)
 		return
 	}
 	if !g.State.SubmitGuess(playerID, d.Guess) {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "cannot submit guess now"}})
+		g.sendError(playerID, "cannot submit guess now")
 		return
 	}
 	g.submitted[playerID] = true
-	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: nil})
+	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: WaitingData{}})
 	g.checkRoundComplete()
 }
 
 func (g *Game) checkRoundComplete() {
+	if g.paused != nil {
+		return // resume checks again
+	}
 	if !g.State.AllSubmitted() {
+		g.checkHurry()
 		return
 	}
 	log.Printf("[game %s] round %d complete, all submitted", g.State.Code, g.State.Round+1)
 	g.stopTimer()
 
-	g.broadcast(OutgoingMessage{Type: MsgRoundComplete, Data: map[string]int{
-		"round": g.State.Round,
+	g.broadcast(OutgoingMessage{Type: MsgRoundComplete, Data: RoundCompleteData{
+		Round: g.State.Round,
 	}})
 
 	if g.State.AdvanceRound() {
-		// Game over — enter reveal phase, prepare for voting
-		g.State.Votes = make(map[string]*PlayerVote)
-		g.State.VotesSubmitted = make(map[string]bool)
-
-		// AI players auto-submit empty votes
-		for _, p := range g.State.Players {
-			if p.Type == AIPlayer {
-				g.State.VotesSubmitted[p.ID] = true
-				g.State.Votes[p.ID] = &PlayerVote{}
-			}
-		}
-
-		g.broadcast(OutgoingMessage{Type: MsgGameOver, Data: map[string]interface{}{
-			"chains": g.State.GetChains(),
-			"scores": g.State.Scores,
-		}})
+		g.enterReveal()
 		return
 	}
 
+	g.admitLateJoiners()
+
 	// Small delay before next turn
 	time.AfterFunc(500*time.Millisecond, func() {
 		g.mu.Lock()
@@ -473,54 +672,98 @@ func (g *Game) checkRoundComplete() {
 	})
 }
 
+// enterReveal moves a finished game to the reveal phase and opens voting.
+func (g *Game) enterReveal() {
+	g.endPause()
+	g.State.Phase = PhaseReveal
+	g.State.Votes = make(map[string]*PlayerVote)
+	g.State.VotesSubmitted = make(map[string]bool)
+
+	// AI players auto-submit empty votes
+	for _, p := range g.State.Players {
+		if p.Type == AIPlayer {
+			g.State.VotesSubmitted[p.ID] = true
+			g.State.Votes[p.ID] = &PlayerVote{}
+		}
+	}
+
+	g.broadcast(OutgoingMessage{Type: MsgGameOver, Data: GameOverData{
+		Chains:          g.State.GetChains(),
+		Scores:          g.State.Scores,
+		VoteTime:        g.voteTime(),
+		AwardCategories: AwardCategories,
+	}})
+	g.startVoteTimer()
+}
+
 func (g *Game) checkAllVotesIn() {
+	if g.State.VotingDone {
+		return
+	}
 	for _, p := range g.State.HumanPlayers() {
 		if !g.State.VotesSubmitted[p.ID] {
 			return
 		}
 	}
 	log.Printf("[game %s] all votes in", g.State.Code)
+	g.stopTimer()
+	g.State.VotingDone = true
+
+	points := make(map[string]int)
 
 	// Tally success votes: each thumbs-up on a chain gives 1 point to the chain owner
 	for _, vote := range g.State.Votes {
 		for _, chainIdx := range vote.SuccessChains {
 			if chainIdx >= 0 && chainIdx < len(g.State.Chains) {
-				ownerID := g.State.Chains[chainIdx].OwnerID
-				g.State.Scores[ownerID]++
+				points[g.State.Chains[chainIdx].OwnerID]++
 			}
 		}
 	}
 
-	// Tally favourite drawing votes: most-picked drawing's author gets bonus point
-	favCounts := make(map[string]int)
-	for _, vote := range g.State.Votes {
-		if vote.FavDrawing != "" {
-			favCounts[vote.FavDrawing]++
-		}
+	// Tally award votes: each category's winning entry's author gets a bonus point
+	awards := g.State.TallyAwards()
+	for _, w := range awards {
+		points[w.PlayerID]++
 	}
-	bestKey := ""
-	bestCount := 0
-	for key, count := range favCounts {
-		if count > bestCount {
-			bestCount = count
-			bestKey = key
-		}
+
+	for id, p := range points {
+		g.State.Scores[id] += p
 	}
-	if bestKey != "" {
-		var ci, ei int
-		if _, err := fmt.Sscanf(bestKey, "%d:%d", &ci, &ei); err == nil {
-			if ci >= 0 && ci < len(g.State.Chains) && ei >= 0 && ei < len(g.State.Chains[ci].Entries) {
-				authorID := g.State.Chains[ci].Entries[ei].PlayerID
-				g.State.Scores[authorID]++
-			}
-		}
+	rec := g.State.RecordGame(points, awards)
+	for team, p := range rec.TeamPoints {
+		g.State.TeamScores[team] += p
 	}
 
-	g.broadcast(OutgoingMessage{Type: MsgScoreUpdate, Data: map[string]interface{}{
-		"scores":      g.State.Scores,
-		"favDrawing":  bestKey,
-		"votingDone":  true,
+	g.broadcast(OutgoingMessage{Type: MsgScoreUpdate, Data: ScoreUpdateData{
+		Scores:     g.State.Scores,
+		TeamScores: g.State.TeamScores,
+		Awards:     awards,
+		Winners:    rec.Winners,
+		VotingDone: true,
 	}})
+	g.broadcast(OutgoingMessage{Type: MsgSessionSummary, Data: g.sessionSummary()})
+}
+
+func (g *Game) sessionSummary() SessionSummaryData {
+	return SessionSummaryData{
+		GamesPlayed: len(g.State.History),
+		Wins:        g.State.SessionWins(),
+		Scores:      g.State.Scores,
+	}
+}
+
+// History returns the room's completed games along with per-player win
+// counts and cumulative scores.
+func (g *Game) History() ([]*GameRecord, map[string]int, map[string]int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	games := make([]*GameRecord, len(g.State.History))
+	copy(games, g.State.History)
+	scores := make(map[string]int, len(g.State.Scores))
+	for id, s := range g.State.Scores {
+		scores[id] = s
+	}
+	return games, g.State.SessionWins(), scores
 }
 
 type kickData struct {
@@ -529,7 +772,7 @@ type kickData struct {
 
 func (g *Game) handleKickPlayer(playerID string, data json.RawMessage) {
 	if playerID != g.State.HostID {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "only host can kick"}})
+		g.sendError(playerID, "only host can kick")
 		return
 	}
 	var d kickData
@@ -539,21 +782,19 @@ func (g *Game) handleKickPlayer(playerID string, data json.RawMessage) {
 	if d.PlayerID == playerID {
 		return // can't kick yourself
 	}
-	g.State.RemovePlayer(d.PlayerID)
-	g.broadcast(OutgoingMessage{Type: MsgPlayerLeft, Data: map[string]interface{}{
-		"playerId": d.PlayerID,
-		"hostId":   g.State.HostID,
-	}})
+	if p := g.State.FindPlayer(d.PlayerID); p != nil {
+		g.removePlayer(p)
+	}
 }
 
 type submitVotesData struct {
-	SuccessChains []int  `json:"successChains"`
-	FavDrawing    string `json:"favDrawing"` // "chainIdx:entryIdx"
+	SuccessChains []int                      `json:"successChains"`
+	Awards        map[AwardCategory]EntryRef `json:"awards"`
 }
 
 func (g *Game) handleSubmitVotes(playerID string, data json.RawMessage) {
 	if g.State.Phase != PhaseReveal {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "not in reveal phase"}})
+		g.sendError(playerID, "not in reveal phase")
 		return
 	}
 	if g.State.VotesSubmitted[playerID] {
@@ -561,28 +802,105 @@ func (g *Game) handleSubmitVotes(playerID string, data json.RawMessage) {
 	}
 	var d submitVotesData
 	if err := json.Unmarshal(data, &d); err != nil {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "invalid data"}})
+		g.sendError(playerID, "invalid data")
+		return
+	}
+	vote, errs := g.State.ValidateVote(playerID, d.SuccessChains, d.Awards)
+	if errs != nil {
+		g.send(playerID, OutgoingMessage{Type: MsgError, Data: ErrorData{
+			Message: "invalid vote",
+			Errors:  errs,
+		}})
 		return
 	}
-	g.State.Votes[playerID] = &PlayerVote{SuccessChains: d.SuccessChains, FavDrawing: d.FavDrawing}
+	g.State.Votes[playerID] = vote
 	g.State.VotesSubmitted[playerID] = true
-	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: nil})
+	g.send(playerID, OutgoingMessage{Type: MsgWaiting, Data: WaitingData{}})
+	g.checkAllVotesIn()
+}
+
+// forceSubmitVotes records an empty vote for every human who hasn't voted.
+func (g *Game) forceSubmitVotes() {
+	for _, p := range g.State.HumanPlayers() {
+		if g.State.VotesSubmitted[p.ID] {
+			continue
+		}
+		g.State.Votes[p.ID] = &PlayerVote{}
+		g.State.VotesSubmitted[p.ID] = true
+	}
+}
+
+func (g *Game) handleEndVoting(playerID string) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can end voting")
+		return
+	}
+	if g.State.Phase != PhaseReveal {
+		g.sendError(playerID, "not in reveal phase")
+		return
+	}
+	if g.State.VotingDone {
+		return
+	}
+	log.Printf("[game %s] voting ended early by host", g.State.Code)
+	g.forceSubmitVotes()
 	g.checkAllVotesIn()
 }
 
 func (g *Game) handlePlayAgain(playerID string) {
 	if playerID != g.State.HostID {
-		g.send(playerID, OutgoingMessage{Type: MsgError, Data: map[string]string{"message": "only host can restart"}})
+		g.sendError(playerID, "only host can restart")
 		return
 	}
 	log.Printf("[game %s] play again requested by host", g.State.Code)
+	g.returnToLobby(ReturnToLobbyData{})
+}
+
+type abortGameData struct {
+	KeepChains bool `json:"keepChains,omitempty"` // include the unfinished chains in return_to_lobby
+}
+
+// handleAbortGame ends the current game early from any phase. No points
+// are awarded and nothing is added to the session history.
+func (g *Game) handleAbortGame(playerID string, data json.RawMessage) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can abort")
+		return
+	}
+	var d abortGameData
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &d); err != nil {
+			g.sendError(playerID, "invalid data")
+			return
+		}
+	}
+	log.Printf("[game %s] aborted by host in phase %d", g.State.Code, g.State.Phase)
+
+	lobby := ReturnToLobbyData{Aborted: true}
+	if d.KeepChains && g.State.Phase != PhaseLobby {
+		lobby.Chains = g.State.Chains
+	}
+	g.returnToLobby(lobby)
+}
+
+// returnToLobby stops every clock, cancels outstanding AI calls and sends
+// everyone back to the lobby, keeping players and scores. The lobby fields
+// of data are filled in; the caller sets the rest.
+func (g *Game) returnToLobby(data ReturnToLobbyData) {
+	g.stopTimer()
+	g.stopChainTimers()
+	g.endPause()
+	g.takenOver = make(map[string]*Player)
+	g.aiCancel()
+	g.aiCtx, g.aiCancel = context.WithCancel(context.Background())
 	g.State.ResetForNewGame()
 	g.submitted = make(map[string]bool)
-	g.broadcast(OutgoingMessage{Type: MsgReturnToLobby, Data: map[string]interface{}{
-		"players": g.State.Players,
-		"scores":  g.State.Scores,
-		"hostId":  g.State.HostID,
-	}})
+
+	data.Players = g.State.Players
+	data.Scores = g.State.Scores
+	data.TeamScores = g.State.TeamScores
+	data.HostID = g.State.HostID
+	g.broadcast(OutgoingMessage{Type: MsgReturnToLobby, Data: data})
 }
 
 func (g *Game) IsEmpty() bool {
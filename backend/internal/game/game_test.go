@@ -0,0 +1,341 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder captures messages sent by a Game.
+type recorder struct {
+	mu        sync.Mutex
+	sent      map[string][]OutgoingMessage
+	broadcast []OutgoingMessage
+}
+
+func newRecorder() *recorder {
+	return &recorder{sent: make(map[string][]OutgoingMessage)}
+}
+
+func (r *recorder) send(playerID string, msg OutgoingMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent[playerID] = append(r.sent[playerID], msg)
+}
+
+func (r *recorder) bcast(msg OutgoingMessage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.broadcast = append(r.broadcast, msg)
+}
+
+// lastSent returns the most recent message of the given type sent to playerID.
+func (r *recorder) lastSent(playerID, msgType string) *OutgoingMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	msgs := r.sent[playerID]
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Type == msgType {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
+
+// lastBroadcast returns the most recent broadcast of the given type.
+func (r *recorder) lastBroadcast(msgType string) *OutgoingMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.broadcast) - 1; i >= 0; i-- {
+		if r.broadcast[i].Type == msgType {
+			return &r.broadcast[i]
+		}
+	}
+	return nil
+}
+
+// newTestGame returns a lobby game with n human players.
+func newTestGame(n int) (*Game, *recorder) {
+	rec := newRecorder()
+	g := NewGame("TEST1", NewHumanPlayer("P0"), rec.send, rec.bcast, nil)
+	for i := 1; i < n; i++ {
+		g.State.AddPlayer(NewHumanPlayer("P" + string(rune('0'+i))))
+	}
+	return g, rec
+}
+
+// newModeGame returns a started game in the given mode with n human
+// players, each option adjusting the state before the start.
+func newModeGame(t *testing.T, mode GameMode, n int, opts ...func(*GameState)) (*Game, *recorder) {
+	t.Helper()
+	g, rec := newTestGame(n)
+	g.HandleMessage(g.State.HostID, msg(t, MsgUpdateSettings, map[string]string{"mode": string(mode)}))
+	for _, opt := range opts {
+		opt(g.State)
+	}
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgStartGame})
+	if g.State.Phase != PhasePlaying {
+		t.Fatalf("Phase = %d, want PhasePlaying", g.State.Phase)
+	}
+	t.Cleanup(func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.stopTimer()
+		g.stopChainTimers()
+		g.aiCancel()
+		for _, t := range g.graceTimers {
+			t.Stop()
+		}
+	})
+	return g, rec
+}
+
+func msg(t *testing.T, msgType string, data interface{}) IncomingMessage {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return IncomingMessage{Type: msgType, Data: raw}
+}
+
+// playToReveal starts the game and submits blanks for every turn.
+func playToReveal(t *testing.T, g *Game) {
+	t.Helper()
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgStartGame})
+	g.mu.Lock()
+	for g.State.Phase == PhasePlaying {
+		g.forceSubmitAll()
+		g.checkRoundComplete()
+		g.submitted = make(map[string]bool)
+	}
+	g.mu.Unlock()
+	if g.State.Phase != PhaseReveal {
+		t.Fatalf("Phase = %d, want PhaseReveal", g.State.Phase)
+	}
+}
+
+func TestEndVoting_HostFinalises(t *testing.T) {
+	g, rec := newTestGame(3)
+	playToReveal(t, g)
+
+	voter := g.State.Players[1].ID
+	g.HandleMessage(voter, msg(t, MsgSubmitVotes, submitVotesData{SuccessChains: []int{0}}))
+	if g.State.VotingDone {
+		t.Fatal("VotingDone should be false while humans are still voting")
+	}
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgEndVoting})
+
+	if !g.State.VotingDone {
+		t.Fatal("VotingDone should be true after end_voting")
+	}
+	if rec.lastBroadcast(MsgScoreUpdate) == nil {
+		t.Error("expected score_update broadcast")
+	}
+	if got := g.State.Scores[g.State.Chains[0].OwnerID]; got != 1 {
+		t.Errorf("chain 0 owner score = %d, want 1", got)
+	}
+	if g.timer != nil {
+		t.Error("vote timer should be stopped")
+	}
+	if len(g.State.History) != 1 {
+		t.Errorf("History len = %d, want 1", len(g.State.History))
+	}
+	if rec.lastBroadcast(MsgSessionSummary) == nil {
+		t.Error("expected session_summary broadcast")
+	}
+}
+
+func TestEndVoting_NonHostRejected(t *testing.T) {
+	g, rec := newTestGame(2)
+	playToReveal(t, g)
+
+	other := g.State.Players[1].ID
+	g.HandleMessage(other, IncomingMessage{Type: MsgEndVoting})
+
+	if g.State.VotingDone {
+		t.Error("non-host should not be able to end voting")
+	}
+	if rec.lastSent(other, MsgError) == nil {
+		t.Error("expected error for non-host")
+	}
+}
+
+func TestVoteTimerExpiry_SubmitsEmptyVotes(t *testing.T) {
+	g, _ := newTestGame(2)
+	playToReveal(t, g)
+
+	g.mu.Lock()
+	g.forceSubmitVotes()
+	g.checkAllVotesIn()
+	g.mu.Unlock()
+
+	for _, p := range g.State.HumanPlayers() {
+		if !g.State.VotesSubmitted[p.ID] {
+			t.Errorf("player %s should have an auto-submitted vote", p.ID)
+		}
+	}
+	if !g.State.VotingDone {
+		t.Error("VotingDone should be true after expiry")
+	}
+}
+
+func TestStartGame_TeamModeRequiresTeams(t *testing.T) {
+	g, rec := newTestGame(2)
+	host := g.State.HostID
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]bool{"teamMode": true}))
+	if !g.State.Settings.TeamMode {
+		t.Fatal("TeamMode should be enabled")
+	}
+
+	g.HandleMessage(host, IncomingMessage{Type: MsgStartGame})
+	if g.State.Phase != PhaseLobby {
+		t.Fatal("game should not start without teams")
+	}
+	if rec.lastSent(host, MsgError) == nil {
+		t.Error("expected error for missing teams")
+	}
+
+	for i, p := range g.State.Players {
+		g.HandleMessage(host, msg(t, MsgAssignTeam, assignTeamData{PlayerID: p.ID, Team: i + 1}))
+	}
+	if rec.lastBroadcast(MsgTeamsUpdated) == nil {
+		t.Error("expected teams_updated broadcast")
+	}
+	g.HandleMessage(host, IncomingMessage{Type: MsgStartGame})
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stopTimer()
+	if g.State.Phase != PhasePlaying {
+		t.Errorf("Phase = %d, want PhasePlaying", g.State.Phase)
+	}
+}
+
+func TestUpdateSettings_HostOnlyInLobby(t *testing.T) {
+	g, rec := newTestGame(2)
+	other := g.State.Players[1].ID
+
+	g.HandleMessage(other, msg(t, MsgUpdateSettings, map[string]bool{"teamMode": true}))
+	if g.State.Settings.TeamMode {
+		t.Error("non-host should not change settings")
+	}
+	if rec.lastSent(other, MsgError) == nil {
+		t.Error("expected error for non-host")
+	}
+}
+
+func TestUpdateSettings_RejectsUnknownRotation(t *testing.T) {
+	g, rec := newTestGame(2)
+	host := g.State.HostID
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]string{"rotation": "spiral"}))
+	if g.State.Settings.Rotation != RotationNeighbour {
+		t.Errorf("Rotation = %q, want unchanged", g.State.Settings.Rotation)
+	}
+	if rec.lastSent(host, MsgError) == nil {
+		t.Error("expected error for unknown rotation")
+	}
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]string{"rotation": string(RotationShuffle)}))
+	if g.State.Settings.Rotation != RotationShuffle {
+		t.Errorf("Rotation = %q, want %q", g.State.Settings.Rotation, RotationShuffle)
+	}
+}
+
+func TestUpdateSettings_Rounds(t *testing.T) {
+	g, rec := newTestGame(2)
+	host := g.State.HostID
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]int{"rounds": MaxRounds + 1}))
+	if g.State.Settings.Rounds != 0 {
+		t.Errorf("Rounds = %d, want unchanged", g.State.Settings.Rounds)
+	}
+	if rec.lastSent(host, MsgError) == nil {
+		t.Error("expected error for out-of-range rounds")
+	}
+
+	g.HandleMessage(host, msg(t, MsgUpdateSettings, map[string]int{"rounds": 6}))
+	if g.State.Settings.Rounds != 6 {
+		t.Errorf("Rounds = %d, want 6", g.State.Settings.Rounds)
+	}
+}
+
+// blockingAI holds every call until its context is cancelled.
+type blockingAI struct {
+	started chan struct{}
+}
+
+func (b *blockingAI) GuessDrawing(ctx context.Context, _ string) (string, error) {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (b *blockingAI) DrawPrompt(ctx context.Context, _ string) (string, error) {
+	b.started <- struct{}{}
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestAbortGame_ReturnsToLobby(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	g.HandleMessage(g.State.Players[1].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+
+	g.HandleMessage(g.State.Players[1].ID, IncomingMessage{Type: MsgAbortGame})
+	if g.State.Phase != PhasePlaying {
+		t.Fatal("non-host should not abort")
+	}
+
+	g.HandleMessage(g.State.HostID, msg(t, MsgAbortGame, abortGameData{KeepChains: true}))
+	if g.State.Phase != PhaseLobby {
+		t.Fatalf("Phase = %d, want PhaseLobby", g.State.Phase)
+	}
+	if g.timer != nil {
+		t.Error("turn timer should be stopped")
+	}
+	if len(g.State.History) != 0 {
+		t.Error("aborted game should not be recorded")
+	}
+	m := rec.lastBroadcast(MsgReturnToLobby)
+	if m == nil {
+		t.Fatal("expected return_to_lobby broadcast")
+	}
+	data := m.Data.(ReturnToLobbyData)
+	if !data.Aborted {
+		t.Error("expected aborted flag")
+	}
+	if len(data.Chains) != 3 || len(data.Chains[1].Entries) != 1 {
+		t.Errorf("expected partial chains in payload, got %v", data.Chains)
+	}
+}
+
+func TestAbortGame_CancelsAICalls(t *testing.T) {
+	ai := &blockingAI{started: make(chan struct{}, 1)}
+	rec := newRecorder()
+	g := NewGame("TEST1", NewHumanPlayer("P0"), rec.send, rec.bcast, ai)
+	g.State.AddPlayer(NewAIPlayer())
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgStartGame})
+	<-ai.started
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgAbortGame})
+	if g.State.Phase != PhaseLobby {
+		t.Fatalf("Phase = %d, want PhaseLobby", g.State.Phase)
+	}
+
+	// The cancelled call must not submit into the next game.
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgStartGame})
+	<-ai.started
+	time.Sleep(20 * time.Millisecond)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, c := range g.State.Chains {
+		if len(c.Entries) != 0 {
+			t.Errorf("chain %d has %d entries, want 0", i, len(c.Entries))
+		}
+	}
+	g.stopTimer()
+	g.aiCancel()
+}
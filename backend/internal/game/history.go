@@ -0,0 +1,84 @@
+package game
+
+import "time"
+
+// GameRecord is a completed game kept in the room's session history.
+type GameRecord struct {
+	Number  int                           `json:"number"` // 1-based within the session
+	EndedAt time.Time                     `json:"endedAt"`
+	Players map[string]string             `json:"players"` // playerID → name at the time
+	Chains  []*Chain                      `json:"chains"`
+	Votes   map[string]*PlayerVote        `json:"votes"`
+	Points  map[string]int                `json:"points"`  // points earned in this game
+	Scores  map[string]int                `json:"scores"`  // cumulative scores after this game
+	Winners []string                      `json:"winners"` // player IDs with the most points (or on the top team) this game
+	Awards  map[AwardCategory]AwardWinner `json:"awards"`
+
+	Teams      map[string]int `json:"teams,omitempty"`      // playerID → team, team mode only
+	TeamPoints map[int]int    `json:"teamPoints,omitempty"` // team → points earned in this game
+}
+
+// RecordGame appends the just-tallied game to the session history.
+func (gs *GameState) RecordGame(points map[string]int, awards map[AwardCategory]AwardWinner) *GameRecord {
+	players := make(map[string]string, len(gs.Players))
+	for _, p := range gs.Players {
+		players[p.ID] = p.Name
+	}
+	scores := make(map[string]int, len(gs.Scores))
+	for id, s := range gs.Scores {
+		scores[id] = s
+	}
+
+	rec := &GameRecord{
+		Number:  len(gs.History) + 1,
+		EndedAt: time.Now(),
+		Players: players,
+		Chains:  gs.Chains,
+		Votes:   gs.Votes,
+		Points:  points,
+		Scores:  scores,
+		Winners: topScorers(points),
+		Awards:  awards,
+	}
+	if gs.Settings.TeamMode {
+		rec.Teams = make(map[string]int, len(gs.Teams))
+		for id, t := range gs.Teams {
+			rec.Teams[id] = t
+		}
+		rec.TeamPoints = gs.TeamPoints(points)
+		rec.Winners = nil
+		for _, t := range topScorers(rec.TeamPoints) {
+			rec.Winners = append(rec.Winners, gs.TeamMembers(t)...)
+		}
+	}
+	gs.History = append(gs.History, rec)
+	return rec
+}
+
+// topScorers returns every key tied on the highest positive score, in no
+// particular order.
+func topScorers[K comparable](points map[K]int) []K {
+	best := 0
+	var ids []K
+	for id, p := range points {
+		switch {
+		case p > best:
+			best = p
+			ids = []K{id}
+		case p == best && p > 0:
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SessionWins counts games won per player across the session history.
+func (gs *GameState) SessionWins() map[string]int {
+	wins := make(map[string]int)
+	for _, rec := range gs.History {
+		for _, id := range rec.Winners {
+			wins[id]++
+		}
+	}
+	return wins
+}
@@ -0,0 +1,73 @@
+package game
+
+import "testing"
+
+func TestRecordGame(t *testing.T) {
+	gs := setupReveal()
+	a, b := gs.Players[0].ID, gs.Players[1].ID
+	gs.Scores[a] = 3
+	gs.Scores[b] = 1
+
+	rec := gs.RecordGame(map[string]int{a: 3, b: 1}, nil)
+
+	if rec.Number != 1 {
+		t.Errorf("Number = %d, want 1", rec.Number)
+	}
+	if len(gs.History) != 1 || gs.History[0] != rec {
+		t.Fatalf("History = %v, want [rec]", gs.History)
+	}
+	if len(rec.Winners) != 1 || rec.Winners[0] != a {
+		t.Errorf("Winners = %v, want [%s]", rec.Winners, a)
+	}
+	if rec.Players[a] != gs.Players[0].Name {
+		t.Errorf("Players[%s] = %q, want %q", a, rec.Players[a], gs.Players[0].Name)
+	}
+
+	// Later score changes must not leak into the record
+	gs.Scores[a] = 10
+	if rec.Scores[a] != 3 {
+		t.Errorf("record Scores[%s] = %d, want 3", a, rec.Scores[a])
+	}
+
+	// Chains survive the reset for the next game
+	chains := gs.Chains
+	gs.ResetForNewGame()
+	if len(rec.Chains) != len(chains) {
+		t.Errorf("record Chains len = %d, want %d", len(rec.Chains), len(chains))
+	}
+	if len(gs.History) != 1 {
+		t.Errorf("History should survive ResetForNewGame, len = %d", len(gs.History))
+	}
+}
+
+func TestTopScorers(t *testing.T) {
+	tests := []struct {
+		name   string
+		points map[string]int
+		want   int
+	}{
+		{"single winner", map[string]int{"a": 2, "b": 1}, 1},
+		{"tie", map[string]int{"a": 2, "b": 2, "c": 1}, 2},
+		{"nobody scored", map[string]int{"a": 0}, 0},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := topScorers(tt.points); len(got) != tt.want {
+				t.Errorf("topScorers(%v) = %v, want %d winners", tt.points, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionWins(t *testing.T) {
+	gs := setupGame(2)
+	gs.RecordGame(map[string]int{"a": 2, "b": 1}, nil)
+	gs.RecordGame(map[string]int{"a": 1, "b": 1}, nil)
+	gs.RecordGame(map[string]int{"b": 4}, nil)
+
+	wins := gs.SessionWins()
+	if wins["a"] != 2 || wins["b"] != 2 {
+		t.Errorf("wins = %v, want a:2 b:2", wins)
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import "log"
+
+// Late joiners: players who join after the game has started wait as
+// spectators. They move into the lobby for the next game, or — with the
+// next_round setting in lockstep play — get a seat and a fresh chain at the
+// next drawing round.
+
+// LateJoinMode selects what happens to players who join a running game.
+type LateJoinMode string
+
+const (
+	// LateJoinSpectate lets newcomers watch until the next game.
+	LateJoinSpectate LateJoinMode = "spectate"
+	// LateJoinNextRound seats newcomers at the next round boundary that
+	// starts a drawing round, with their own fresh chain.
+	LateJoinNextRound LateJoinMode = "next_round"
+)
+
+func (m LateJoinMode) valid() bool {
+	return m == LateJoinSpectate || m == LateJoinNextRound
+}
+
+// FindSpectator returns the spectator with the given ID, or nil.
+func (gs *GameState) FindSpectator(id string) *Player {
+	for _, p := range gs.Spectators {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// RemoveSpectator drops a spectator. Returns false if there was none.
+func (gs *GameState) RemoveSpectator(id string) bool {
+	for i, p := range gs.Spectators {
+		if p.ID == id {
+			gs.Spectators = append(gs.Spectators[:i], gs.Spectators[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// seatSpectators moves every spectator into the player list.
+func (gs *GameState) seatSpectators() {
+	for _, p := range gs.Spectators {
+		gs.AddPlayer(p)
+	}
+	gs.Spectators = nil
+}
+
+// InsertSpectators seats the spectators mid-game at the current round,
+// each with a fresh chain. Only possible in lockstep play at the start of
+// a drawing round with at least two rounds left. Existing chains keep
+// their entries and go to whoever the old rotation gave them this round;
+// from there they're passed around the grown table, and a default chain
+// length grows with it. Returns the players seated.
+func (gs *GameState) InsertSpectators() []*Player {
+	if len(gs.Spectators) == 0 || gs.Settings.Mode != ModeSync || gs.Settings.TeamMode {
+		return nil
+	}
+	if roundTurnType(gs.Round) != TurnDraw {
+		return nil
+	}
+	n := len(gs.Players) + len(gs.Spectators)
+	total := gs.TotalRounds
+	if r := gs.Settings.roundsFor(n); r > total && gs.Settings.Rounds == 0 {
+		total = r
+	}
+	if total-gs.Round < 2 {
+		return nil
+	}
+
+	words := gs.unusedWords(len(gs.Spectators))
+	joined := gs.Spectators
+	for i, p := range joined {
+		gs.AddPlayer(p)
+		gs.Chains = append(gs.Chains, &Chain{
+			OriginalWord: words[i],
+			OwnerID:      p.ID,
+			StartRound:   gs.Round,
+		})
+	}
+	gs.Spectators = nil
+	gs.TotalRounds = total
+	next := buildRotation(gs.Settings.Rotation, n, total-gs.Round, nil)
+	gs.Rotation = gs.Rotation.grow(gs.Round, total, next)
+	return joined
+}
+
+// unusedWords picks n random words not already starting a chain.
+func (gs *GameState) unusedWords(n int) []string {
+	used := make(map[string]bool)
+	for _, c := range gs.Chains {
+		used[c.OriginalWord] = true
+	}
+	var words []string
+	for _, w := range RandomWords(n + len(gs.Chains)) {
+		if len(words) == n {
+			break
+		}
+		if !used[w] {
+			used[w] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// joinAsSpectator adds a player who joined mid-game.
+func (g *Game) joinAsSpectator(player *Player) {
+	g.State.Spectators = append(g.State.Spectators, player)
+	log.Printf("[game %s] %s joined mid-game as a spectator", g.State.Code, player.Name)
+	g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: PlayerJoinedData{
+		Player:    player,
+		Spectator: true,
+	}})
+	g.sendGameState(player.ID)
+}
+
+// admitLateJoiners seats waiting spectators at a round boundary if the
+// game allows it.
+func (g *Game) admitLateJoiners() {
+	if g.State.Settings.LateJoin != LateJoinNextRound {
+		return
+	}
+	joined := g.State.InsertSpectators()
+	for _, p := range joined {
+		log.Printf("[game %s] %s seated at round %d", g.State.Code, p.Name, g.State.Round+1)
+		g.broadcast(OutgoingMessage{Type: MsgPlayerJoined, Data: PlayerJoinedData{
+			Player:      p,
+			Round:       g.State.Round,
+			TotalRounds: g.State.TotalRounds,
+		}})
+	}
+}
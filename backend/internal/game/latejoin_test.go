@@ -0,0 +1,121 @@
+package game
+
+import "testing"
+
+// finishRound submits blanks for everyone still working on the round.
+func finishRound(g *Game) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.forceSubmitAll()
+	g.checkRoundComplete()
+	g.submitted = make(map[string]bool)
+}
+
+func TestLateJoin_Spectates(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	late := NewHumanPlayer("Late")
+
+	g.HandleJoin(late)
+	if g.State.FindPlayer(late.ID) != nil {
+		t.Fatal("late joiner should not be seated mid-game")
+	}
+	if g.State.FindSpectator(late.ID) == nil {
+		t.Fatal("late joiner should be a spectator")
+	}
+	if g.State.FindPlayerByToken(late.Token) != late {
+		t.Error("spectators must be able to connect with their token")
+	}
+	if rec.lastSent(late.ID, MsgGameState) == nil {
+		t.Error("expected game_state for the spectator")
+	}
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgAbortGame})
+	if g.State.FindPlayer(late.ID) == nil {
+		t.Error("spectator should be seated for the next game")
+	}
+	if len(g.State.Spectators) != 0 {
+		t.Errorf("Spectators = %d, want 0", len(g.State.Spectators))
+	}
+}
+
+func TestLateJoin_SpectatorDisconnect(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2)
+	late := NewHumanPlayer("Late")
+	g.HandleJoin(late)
+
+	g.HandleDisconnect(late.ID)
+	if g.State.FindSpectator(late.ID) != nil {
+		t.Error("spectator should be removed on disconnect")
+	}
+	if rec.lastBroadcast(MsgPlayerLeft) == nil {
+		t.Error("expected player_left broadcast")
+	}
+}
+
+func TestLateJoin_NextRoundInsertsAtDrawingRound(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 3, func(s *GameState) { s.Settings.LateJoin = LateJoinNextRound })
+
+	late := NewHumanPlayer("Late")
+	g.HandleJoin(late)
+
+	finishRound(g) // round 1 → 2 is a guessing round: keep waiting
+	if g.State.FindPlayer(late.ID) != nil {
+		t.Fatal("should not be seated before a guessing round")
+	}
+
+	finishRound(g) // round 2 → 3 is a drawing round
+	p := g.State.FindPlayer(late.ID)
+	if p == nil {
+		t.Fatal("late joiner should be seated at the drawing round")
+	}
+	if g.State.TotalRounds != 4 {
+		t.Errorf("TotalRounds = %d, want 4", g.State.TotalRounds)
+	}
+	chain := g.State.Chains[p.Index]
+	if chain.OwnerID != late.ID || chain.StartRound != 2 {
+		t.Errorf("fresh chain = %+v, want owner %s starting at round 2", chain, late.ID)
+	}
+
+	for g.State.Phase == PhasePlaying {
+		finishRound(g)
+	}
+	if g.State.Phase != PhaseReveal {
+		t.Fatalf("Phase = %d, want PhaseReveal", g.State.Phase)
+	}
+	for i, c := range g.State.Chains {
+		want := g.State.TotalRounds - c.StartRound
+		if len(c.Entries) != want {
+			t.Errorf("chain %d has %d entries, want %d", i, len(c.Entries), want)
+		}
+		for j, e := range c.Entries {
+			if e.Type != roundTurnType(j) {
+				t.Errorf("chain %d entry %d type = %d, want %d", i, j, e.Type, roundTurnType(j))
+			}
+			if j > 0 && e.PlayerID == c.Entries[j-1].PlayerID {
+				t.Errorf("chain %d: %s works on it twice in a row (entries %d, %d)", i, e.PlayerID, j-1, j)
+			}
+		}
+	}
+	if e := chain.Entries; len(e) == 0 || e[0].PlayerID != late.ID {
+		t.Errorf("fresh chain entries = %+v, want %s to start it", e, late.ID)
+	}
+}
+
+func TestLateJoin_NextRoundNeedsTwoRoundsLeft(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 3, func(s *GameState) {
+		s.Settings.LateJoin = LateJoinNextRound
+		s.Settings.Rounds = 3
+	})
+
+	late := NewHumanPlayer("Late")
+	g.HandleJoin(late)
+	finishRound(g)
+	finishRound(g) // round 3 of 3 is a drawing round, but only one is left
+
+	if g.State.FindPlayer(late.ID) != nil {
+		t.Error("should not be seated for a single round")
+	}
+	if g.State.FindSpectator(late.ID) == nil {
+		t.Error("should still be spectating")
+	}
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// Host pause: freezes every running clock — the round countdown and its
+// ticks, per-player deadlines and per-chain turn timers — and holds back
+// submissions until the host resumes. A pause that runs past the
+// configured limit resumes or aborts on its own.
+
+// PauseTimeoutAction is what happens when a pause runs past its limit.
+type PauseTimeoutAction string
+
+const (
+	PauseTimeoutResume PauseTimeoutAction = "resume"
+	PauseTimeoutAbort  PauseTimeoutAction = "abort" // return everyone to the lobby
+)
+
+func (a PauseTimeoutAction) valid() bool {
+	return a == PauseTimeoutResume || a == PauseTimeoutAbort
+}
+
+// MaxPauseMinutes caps the configurable pause limit.
+const MaxPauseMinutes = 60
+
+// pauseState holds what a pause froze, restored on resume.
+type pauseState struct {
+	countdown    time.Duration            // left on the round countdown
+	hasCountdown bool                     // a round countdown was running
+	players      map[string]time.Duration // left on per-player deadlines
+	chains       map[int]time.Duration    // left on per-chain turn timers
+	ai           []heldAITurn             // AI turns that finished while paused
+	turnPending  bool                     // the next round was due to start
+	timeout      *time.Timer
+}
+
+type heldAITurn struct {
+	info   TurnInfo
+	result string
+}
+
+func (g *Game) handlePauseGame(playerID string) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can pause")
+		return
+	}
+	if err := g.pause(); err != nil {
+		g.sendError(playerID, err.Error())
+	}
+}
+
+func (g *Game) handleResumeGame(playerID string) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can resume")
+		return
+	}
+	if err := g.resume(); err != nil {
+		g.sendError(playerID, err.Error())
+	}
+}
+
+// pause stops every clock and records how long each had left.
+func (g *Game) pause() error {
+	if g.State.Phase != PhasePlaying {
+		return errors.New("game is not in progress")
+	}
+	if g.paused != nil {
+		return errors.New("game is already paused")
+	}
+	p := &pauseState{
+		players: make(map[string]time.Duration),
+		chains:  make(map[int]time.Duration),
+	}
+
+	if g.timer != nil {
+		p.countdown = time.Until(g.deadline)
+		p.hasCountdown = true
+		g.timer.Stop()
+		g.timer = nil
+	}
+	if g.tickCancel != nil {
+		close(g.tickCancel)
+		g.tickCancel = nil
+	}
+	for id, d := range g.playerDeadlines {
+		p.players[id] = time.Until(d)
+	}
+	for id, t := range g.playerTimers {
+		t.Stop()
+		delete(g.playerTimers, id)
+	}
+	for ci, t := range g.chainTimers {
+		t.Stop()
+		p.chains[ci] = time.Until(g.chainDeadlines[ci])
+		delete(g.chainTimers, ci)
+		delete(g.chainDeadlines, ci)
+	}
+
+	limit := time.Duration(g.State.Settings.MaxPauseMinutes) * time.Minute
+	var t *time.Timer
+	t = time.AfterFunc(limit, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.paused == nil || g.paused.timeout != t {
+			return // resumed while waiting for the lock
+		}
+		g.pauseExpired()
+	})
+	p.timeout = t
+	g.paused = p
+
+	g.State.Paused = true
+	g.State.PausedRemaining = int((p.countdown + time.Second - 1) / time.Second)
+	log.Printf("[game %s] paused with %ds left", g.State.Code, g.State.PausedRemaining)
+	g.broadcast(OutgoingMessage{Type: MsgGamePaused, Data: GamePausedData{
+		Remaining: g.State.PausedRemaining,
+		MaxPause:  int(limit.Seconds()),
+		OnTimeout: g.State.Settings.PauseTimeout,
+	}})
+	return nil
+}
+
+// resume restarts every clock with the time it had left, then applies
+// anything held back during the pause.
+func (g *Game) resume() error {
+	p := g.paused
+	if p == nil {
+		return errors.New("game is not paused")
+	}
+	g.endPause()
+	now := time.Now()
+
+	if p.hasCountdown {
+		g.setDeadline(now.Add(p.countdown))
+		g.startTicks(PhasePlaying, MsgTurnTick)
+	}
+	for id, left := range p.players {
+		d := now.Add(left)
+		g.playerDeadlines[id] = d
+		if d.Before(g.deadline) && !g.submitted[id] {
+			g.startPlayerTimer(id, d)
+		}
+	}
+	for ci, left := range p.chains {
+		if info, ok := g.State.ChainTurn(ci); ok {
+			g.startChainTimer(info, left)
+		}
+	}
+
+	if p.turnPending && g.State.Phase == PhasePlaying {
+		g.startTurn()
+	}
+
+	log.Printf("[game %s] resumed", g.State.Code)
+	g.broadcast(OutgoingMessage{Type: MsgGameResumed, Data: GameResumedData{
+		Remaining: g.secondsLeft(),
+	}})
+
+	for _, h := range p.ai {
+		g.submitAITurn(h.info, h.result)
+	}
+	if g.State.Settings.Mode == ModeSync && g.State.Phase == PhasePlaying {
+		// Players who left during the pause may have completed the round.
+		g.checkRoundComplete()
+	}
+	return nil
+}
+
+// pauseExpired handles a pause that ran past the configured limit.
+func (g *Game) pauseExpired() {
+	log.Printf("[game %s] pause limit reached, %s", g.State.Code, g.State.Settings.PauseTimeout)
+	if g.State.Settings.PauseTimeout == PauseTimeoutAbort {
+		g.returnToLobby(ReturnToLobbyData{Aborted: true})
+		return
+	}
+	g.resume()
+}
+
+// endPause clears the pause without restarting anything.
+func (g *Game) endPause() {
+	if g.paused == nil {
+		return
+	}
+	g.paused.timeout.Stop()
+	g.paused = nil
+	g.State.Paused = false
+	g.State.PausedRemaining = 0
+}
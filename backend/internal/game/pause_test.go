@@ -0,0 +1,203 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPause_FreezesAndResumesCountdown(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	host := g.State.HostID
+
+	g.HandleMessage(host, IncomingMessage{Type: MsgPauseGame})
+	if !g.State.Paused {
+		t.Fatal("expected game to be paused")
+	}
+	if g.timer != nil || g.tickCancel != nil {
+		t.Error("countdown and ticks should be stopped while paused")
+	}
+	if got := g.State.PausedRemaining; got < g.State.TurnTime-1 || got > g.State.TurnTime {
+		t.Errorf("PausedRemaining = %d, want ~%d", got, g.State.TurnTime)
+	}
+	if rec.lastBroadcast(MsgGamePaused) == nil {
+		t.Error("expected game_paused broadcast")
+	}
+
+	g.HandleMessage(host, IncomingMessage{Type: MsgResumeGame})
+	if g.State.Paused {
+		t.Fatal("expected game to be resumed")
+	}
+	if g.timer == nil {
+		t.Fatal("countdown should be running again")
+	}
+	if left := time.Until(g.deadline); left < time.Duration(g.State.TurnTime-1)*time.Second {
+		t.Errorf("deadline in %v after resume, want ~%ds", left, g.State.TurnTime)
+	}
+	if rec.lastBroadcast(MsgGameResumed) == nil {
+		t.Error("expected game_resumed broadcast")
+	}
+}
+
+func TestPause_HostOnly(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2)
+	other := g.State.Players[1].ID
+
+	g.HandleMessage(other, IncomingMessage{Type: MsgPauseGame})
+	if g.State.Paused {
+		t.Error("non-host should not pause")
+	}
+	if rec.lastSent(other, MsgError) == nil {
+		t.Error("expected error for non-host")
+	}
+}
+
+func TestPause_RejectsSubmissions(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2)
+	p := g.State.Players[1].ID
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgPauseGame})
+	g.HandleMessage(p, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+	if g.submitted[p] {
+		t.Error("submission should be rejected while paused")
+	}
+	if rec.lastSent(p, MsgError) == nil {
+		t.Error("expected error for submission while paused")
+	}
+}
+
+func TestPause_HoldsAITurnsUntilResume(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 2)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.pause(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	infos := g.State.GetTurnInfos()
+	for _, info := range infos {
+		g.submitAITurn(info, "held")
+	}
+	if g.State.Round != 0 || len(g.submitted) != 0 {
+		t.Fatal("AI turns should be held while paused")
+	}
+
+	if err := g.resume(); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if g.State.Round != 1 {
+		t.Errorf("Round = %d, want 1 once held turns are applied on resume", g.State.Round)
+	}
+}
+
+func TestPause_ExpiryAborts(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2)
+	g.State.Settings.PauseTimeout = PauseTimeoutAbort
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.pause(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	g.pauseExpired()
+	if g.State.Phase != PhaseLobby {
+		t.Errorf("Phase = %d, want PhaseLobby", g.State.Phase)
+	}
+	if g.State.Paused || g.paused != nil {
+		t.Error("pause should be cleared")
+	}
+	if rec.lastBroadcast(MsgReturnToLobby) == nil {
+		t.Error("expected return_to_lobby broadcast")
+	}
+}
+
+func TestPause_ExpiryResumes(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 2)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.pause(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	g.pauseExpired()
+	if g.State.Paused || g.timer == nil {
+		t.Error("expected the game to resume with its countdown running")
+	}
+}
+
+func TestPause_ChainTimers(t *testing.T) {
+	g, _ := newModeGame(t, ModePipeline, 3)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.chainTimers) == 0 {
+		t.Fatal("expected chain timers to be running")
+	}
+	if err := g.pause(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if len(g.chainTimers) != 0 {
+		t.Error("chain timers should be stopped while paused")
+	}
+	if err := g.resume(); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(g.chainTimers) != len(g.State.Chains) {
+		t.Errorf("chain timers = %d after resume, want %d", len(g.chainTimers), len(g.State.Chains))
+	}
+}
+
+func TestPause_BetweenRoundsHoldsNextTurn(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2)
+	host := g.State.HostID
+	for _, p := range g.State.Players {
+		g.HandleMessage(p.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+	}
+	// The round is complete and the next turn is a moment away.
+	g.HandleMessage(host, IncomingMessage{Type: MsgPauseGame})
+	turns := len(turnStarts(rec, host))
+
+	time.Sleep(700 * time.Millisecond)
+	g.mu.Lock()
+	if g.timer != nil || g.tickCancel != nil {
+		t.Error("next round's countdown started while paused")
+	}
+	if n := len(turnStarts(rec, host)); n != turns {
+		t.Errorf("%d turn_start messages sent while paused", n-turns)
+	}
+	g.mu.Unlock()
+
+	g.HandleMessage(host, IncomingMessage{Type: MsgResumeGame})
+	if g.timer == nil {
+		t.Fatal("resume should start the pending turn's countdown")
+	}
+	if n := len(turnStarts(rec, host)); n != turns+1 {
+		t.Errorf("resume sent %d turn_start messages, want 1", n-turns)
+	}
+	if g.State.Round != 1 {
+		t.Errorf("round = %d, want 1", g.State.Round)
+	}
+}
+
+// A paused chain turn has no deadline, only the time its timer had left.
+func TestPause_ChainTurnReportsFrozenTime(t *testing.T) {
+	g, _ := newModeGame(t, ModePipeline, 3)
+	p1 := g.State.Players[1]
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgPauseGame})
+
+	g.mu.Lock()
+	info, _ := g.State.ChainTurn(1)
+	d := g.chainTurnData(info)
+	want := g.paused.chains[1]
+	g.mu.Unlock()
+	if d.Deadline != nil {
+		t.Errorf("turn_start deadline = %v while paused, want none", d.Deadline)
+	}
+	if secs := int((want + time.Second - 1) / time.Second); d.TimeLimit != secs || secs <= 0 {
+		t.Errorf("turn_start timeLimit = %d, want the %d seconds left at the pause", d.TimeLimit, secs)
+	}
+
+	turns, _ := g.MyTurns(p1.Token)
+	if len(turns) != 1 || !turns[0].Deadline.IsZero() || turns[0].TimeLeft != d.TimeLimit {
+		t.Errorf("pending turns = %+v, want chain 1 with %d seconds left and no deadline", turns, d.TimeLimit)
+	}
+}
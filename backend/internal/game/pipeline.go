@@ -0,0 +1,72 @@
+package game
+
+import "time"
+
+// Pipeline mode queues: a chain's turn joins its holder's queue when it
+// reaches them, and each player works through their queue one turn at a
+// time. The turn timer only starts once a turn is served.
+
+// enqueueTurn adds a chain's pending turn to its holder's queue, serving
+// it immediately if they're free.
+func (g *Game) enqueueTurn(info TurnInfo) {
+	q := append(g.queues[info.PlayerID], info.ChainIdx)
+	g.queues[info.PlayerID] = q
+	if len(q) == 1 {
+		g.serveTurn(info.PlayerID)
+	}
+}
+
+// serveTurn starts the turn at the head of a player's queue.
+func (g *Game) serveTurn(playerID string) {
+	q := g.queues[playerID]
+	if len(q) == 0 {
+		return
+	}
+	info, ok := g.State.ChainTurn(q[0])
+	if !ok || info.PlayerID != playerID {
+		// Stale entry; drop it and move on.
+		g.queues[playerID] = q[1:]
+		g.serveTurn(playerID)
+		return
+	}
+	g.startChainTimer(info, time.Duration(g.State.TurnTime)*time.Second)
+
+	p := g.State.FindPlayer(playerID)
+	if p == nil {
+		return
+	}
+	if p.Type == AIPlayer {
+		go g.handleAITurn(info)
+		return
+	}
+	g.send(playerID, OutgoingMessage{Type: MsgTurnStart, Data: g.chainTurnData(info)})
+}
+
+// dequeueTurn removes a submitted chain from its holder's queue and serves
+// their next turn if it was the current one.
+func (g *Game) dequeueTurn(playerID string, chainIdx int) {
+	q := g.queues[playerID]
+	for i, ci := range q {
+		if ci != chainIdx {
+			continue
+		}
+		g.queues[playerID] = append(q[:i:i], q[i+1:]...)
+		if i == 0 && g.State.Phase == PhasePlaying {
+			g.serveTurn(playerID)
+		}
+		return
+	}
+}
+
+// currentTurn returns the turn a pipeline player is working on, if any.
+func (g *Game) currentTurn(playerID string) []TurnInfo {
+	q := g.queues[playerID]
+	if len(q) == 0 {
+		return nil
+	}
+	info, ok := g.State.ChainTurn(q[0])
+	if !ok {
+		return nil
+	}
+	return []TurnInfo{info}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// turnStarts returns the chain indices of every turn_start sent to a player.
+func turnStarts(rec *recorder, playerID string) []int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	var chains []int
+	for _, m := range rec.sent[playerID] {
+		if m.Type != MsgTurnStart {
+			continue
+		}
+		data, _ := json.Marshal(m.Data)
+		var d struct {
+			ChainIdx int `json:"chainIdx"`
+		}
+		json.Unmarshal(data, &d)
+		chains = append(chains, d.ChainIdx)
+	}
+	return chains
+}
+
+func TestPipeline_NextTurnQueuesUntilPlayerIsFree(t *testing.T) {
+	g, rec := newModeGame(t, ModePipeline, 3)
+	p0, p1 := g.State.Players[0], g.State.Players[1]
+
+	if got := turnStarts(rec, p1.ID); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("p1 turn_starts = %v, want [1]", got)
+	}
+
+	g.HandleMessage(p0.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d0"}))
+
+	// Chain 0 reaches p1, who is still drawing on chain 1.
+	if q := g.queues[p1.ID]; len(q) != 2 || q[0] != 1 || q[1] != 0 {
+		t.Fatalf("p1 queue = %v, want [1 0]", q)
+	}
+	if got := turnStarts(rec, p1.ID); len(got) != 1 {
+		t.Errorf("p1 should not be served chain 0 while busy, turn_starts = %v", got)
+	}
+	if rec.lastSent(p0.ID, MsgWaiting) == nil {
+		t.Error("p0 should be waiting with nothing queued")
+	}
+
+	// p1 can't jump ahead to chain 0.
+	chainIdx := 0
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "g", ChainIdx: &chainIdx}))
+	if r := g.State.ChainRound(0); r != 1 {
+		t.Errorf("chain 0 round = %d, want 1", r)
+	}
+
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d1"}))
+
+	if got := turnStarts(rec, p1.ID); len(got) != 2 || got[1] != 0 {
+		t.Errorf("p1 turn_starts = %v, want chain 0 served next", got)
+	}
+	if _, ok := g.chainTimers[0]; !ok {
+		t.Error("chain 0 timer should start once served")
+	}
+}
+
+func TestPipeline_GameEndsWhenAllChainsComplete(t *testing.T) {
+	g, _ := newModeGame(t, ModePipeline, 3)
+
+	for steps := 0; g.State.Phase == PhasePlaying; steps++ {
+		if steps > 100 {
+			t.Fatal("game did not finish")
+		}
+		for _, p := range g.State.Players {
+			cur := g.currentTurn(p.ID)
+			if len(cur) == 0 {
+				continue
+			}
+			if cur[0].TurnType == TurnDraw {
+				g.HandleMessage(p.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d"}))
+			} else {
+				g.HandleMessage(p.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "g"}))
+			}
+		}
+	}
+
+	if g.State.Phase != PhaseReveal {
+		t.Fatalf("Phase = %d, want PhaseReveal", g.State.Phase)
+	}
+	for i, c := range g.State.Chains {
+		if len(c.Entries) != g.State.TotalRounds {
+			t.Errorf("chain %d has %d entries, want %d", i, len(c.Entries), g.State.TotalRounds)
+		}
+	}
+}
+
+func TestPipeline_DisconnectStandInTakesQueuedTurns(t *testing.T) {
+	g, _ := newModeGame(t, ModePipeline, 3)
+	p0, p1 := g.State.Players[0], g.State.Players[1]
+
+	g.HandleMessage(p0.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d0"}))
+	g.HandleDisconnect(p1.ID)
+
+	waitFor(t, g, func() bool { return g.State.ChainRound(1) == 1 && g.State.ChainRound(0) == 2 })
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.queues[p1.ID]; ok {
+		t.Error("departed player's queue should be cleared")
+	}
+	if s := g.State.playerAt(1); s == nil || s.StandInFor != p1.ID {
+		t.Errorf("seat 1 = %+v, want a stand-in for %s", s, p1.ID)
+	}
+}
@@ -19,15 +19,19 @@ type Player struct {
 	Type  PlayerType `json:"type"`
 	Token string     `json:"-"`
 	Index int        `json:"index"`
+
+	StandInFor string   `json:"standInFor,omitempty"` // ID of the player whose seat this bot took over mid-game
+	Presence   Presence `json:"presence,omitempty"`   // humans only; see SetPresence
 }
 
 func NewHumanPlayer(name string) *Player {
 	token := uuid.New().String()
 	return &Player{
-		ID:    token[:8],
-		Name:  name,
-		Type:  HumanPlayer,
-		Token: token,
+		ID:       token[:8],
+		Name:     name,
+		Type:     HumanPlayer,
+		Token:    token,
+		Presence: PresenceDisconnected, // until their socket connects
 	}
 }
 
@@ -0,0 +1,37 @@
+package game
+
+// Presence shows whether a human player is actually connected. The
+// WebSocket layer reports it: online while the socket is alive and the
+// player active, idle when the socket is alive but they've gone quiet,
+// disconnected while their seat waits for them to come back.
+
+// Presence is a human player's connection status.
+type Presence string
+
+const (
+	PresenceOnline       Presence = "online"
+	PresenceIdle         Presence = "idle"
+	PresenceDisconnected Presence = "disconnected"
+)
+
+// SetPresence records a player's presence, telling everyone if it changed.
+func (g *Game) SetPresence(playerID string, presence Presence) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.setPresence(playerID, presence)
+}
+
+func (g *Game) setPresence(playerID string, presence Presence) {
+	p := g.State.FindPlayer(playerID)
+	if p == nil {
+		p = g.State.FindSpectator(playerID)
+	}
+	if p == nil || p.Type != HumanPlayer || p.Presence == presence {
+		return
+	}
+	p.Presence = presence
+	g.broadcast(OutgoingMessage{Type: MsgPresence, Data: PresenceData{
+		PlayerID: playerID,
+		Presence: presence,
+	}})
+}
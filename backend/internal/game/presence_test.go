@@ -0,0 +1,44 @@
+package game
+
+import "testing"
+
+func TestPresence_ConnectAndDisconnect(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 2, botTakeover)
+	p1 := g.State.Players[1]
+
+	g.HandleReconnect(p1.ID)
+	if p1.Presence != PresenceOnline {
+		t.Errorf("presence after connecting = %s, want online", p1.Presence)
+	}
+	m := rec.lastBroadcast(MsgPresence)
+	if m == nil || m.Data.(PresenceData) != (PresenceData{PlayerID: p1.ID, Presence: PresenceOnline}) {
+		t.Errorf("presence broadcast = %+v", m)
+	}
+
+	g.HandleDisconnect(p1.ID)
+	if p1.Presence != PresenceDisconnected {
+		t.Errorf("presence while the seat is held = %s, want disconnected", p1.Presence)
+	}
+}
+
+func TestPresence_BroadcastsOnlyChanges(t *testing.T) {
+	g, rec := newTestGame(2)
+	p1 := g.State.Players[1].ID
+
+	g.SetPresence(p1, PresenceIdle)
+	g.SetPresence(p1, PresenceIdle)
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgAddAI})
+	g.SetPresence(g.State.Players[2].ID, PresenceIdle)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	n := 0
+	for _, m := range rec.broadcast {
+		if m.Type == MsgPresence {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("presence broadcasts = %d, want 1", n)
+	}
+}
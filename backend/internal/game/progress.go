@@ -0,0 +1,123 @@
+package game
+
+// Per-chain progression, used by modes where chains advance independently
+// instead of in lockstep rounds. A chain's round is simply how many entries
+// it has; the rotation decides who holds it at each round.
+
+// ChainRound returns the round a chain is waiting on.
+func (gs *GameState) ChainRound(chainIdx int) int {
+	return len(gs.Chains[chainIdx].Entries)
+}
+
+// ChainComplete reports whether a chain has all its entries.
+func (gs *GameState) ChainComplete(chainIdx int) bool {
+	return gs.ChainRound(chainIdx) >= gs.TotalRounds
+}
+
+// AllChainsComplete reports whether every chain has all its entries.
+func (gs *GameState) AllChainsComplete() bool {
+	for i := range gs.Chains {
+		if !gs.ChainComplete(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// ChainTurn returns the turn a chain is waiting on, or false if the chain
+// is complete or its holder has left.
+func (gs *GameState) ChainTurn(chainIdx int) (TurnInfo, bool) {
+	if gs.ChainComplete(chainIdx) {
+		return TurnInfo{}, false
+	}
+	round := gs.ChainRound(chainIdx)
+	holder := gs.playerAt(gs.Rotation.HolderOf(chainIdx, round))
+	if holder == nil {
+		return TurnInfo{}, false
+	}
+	turnType := roundTurnType(round)
+	return TurnInfo{
+		PlayerID: holder.ID,
+		ChainIdx: chainIdx,
+		Round:    round,
+		TurnType: turnType,
+		Prompt:   chainPrompt(gs.Chains[chainIdx], turnType),
+	}, true
+}
+
+// PendingTurns returns the turns waiting on a player, in chain order.
+func (gs *GameState) PendingTurns(playerID string) []TurnInfo {
+	var turns []TurnInfo
+	for i := range gs.Chains {
+		if info, ok := gs.ChainTurn(i); ok && info.PlayerID == playerID {
+			turns = append(turns, info)
+		}
+	}
+	return turns
+}
+
+// SubmitToChain records an entry on a chain if it's the given player's
+// turn there, at the given round, and of the given type.
+func (gs *GameState) SubmitToChain(playerID string, chainIdx, round int, turnType TurnType, content string) bool {
+	if chainIdx < 0 || chainIdx >= len(gs.Chains) {
+		return false
+	}
+	info, ok := gs.ChainTurn(chainIdx)
+	if !ok || info.PlayerID != playerID || info.Round != round || info.TurnType != turnType {
+		return false
+	}
+	entry := ChainEntry{PlayerID: playerID, Type: turnType}
+	if turnType == TurnDraw {
+		entry.Drawing = content
+	} else {
+		entry.Guess = content
+	}
+	gs.Chains[chainIdx].AddEntry(entry)
+	return true
+}
+
+// SyncRound sets Round to the number of rounds every chain has finished.
+// Returns true if it moved forward.
+func (gs *GameState) SyncRound() bool {
+	lowest := gs.TotalRounds
+	for i := range gs.Chains {
+		if r := gs.ChainRound(i); r < lowest {
+			lowest = r
+		}
+	}
+	if lowest > gs.Round {
+		gs.Round = lowest
+		return true
+	}
+	return false
+}
+
+// playerAt returns the player with the given seat index, or nil.
+func (gs *GameState) playerAt(idx int) *Player {
+	for _, p := range gs.Players {
+		if p.Index == idx {
+			return p
+		}
+	}
+	return nil
+}
+
+// roundTurnType returns whether a round is for drawing or guessing.
+func roundTurnType(round int) TurnType {
+	if round%2 == 1 {
+		return TurnGuess
+	}
+	return TurnDraw
+}
+
+// chainPrompt returns what the next player on a chain works from.
+func chainPrompt(chain *Chain, turnType TurnType) string {
+	if len(chain.Entries) == 0 {
+		return chain.OriginalWord
+	}
+	last := chain.Entries[len(chain.Entries)-1]
+	if turnType == TurnDraw {
+		return last.Guess
+	}
+	return last.Drawing
+}
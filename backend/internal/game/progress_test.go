@@ -0,0 +1,78 @@
+package game
+
+import "testing"
+
+func TestChainTurn_FollowsRotation(t *testing.T) {
+	gs := setupGame(3)
+
+	for ci := range gs.Chains {
+		info, ok := gs.ChainTurn(ci)
+		if !ok {
+			t.Fatalf("chain %d: no pending turn", ci)
+		}
+		if info.PlayerID != gs.Chains[ci].OwnerID || info.Round != 0 || info.TurnType != TurnDraw {
+			t.Errorf("chain %d: turn = %+v, want owner drawing round 0", ci, info)
+		}
+		if info.Prompt != gs.Chains[ci].OriginalWord {
+			t.Errorf("chain %d: prompt = %q, want original word", ci, info.Prompt)
+		}
+	}
+}
+
+func TestSubmitToChain_AdvancesOneChain(t *testing.T) {
+	gs := setupGame(3)
+	owner := gs.Players[0].ID
+
+	if gs.SubmitToChain(owner, 0, 0, TurnGuess, "cat") {
+		t.Error("SubmitToChain should reject the wrong turn type")
+	}
+	if gs.SubmitToChain(gs.Players[1].ID, 0, 0, TurnDraw, "d") {
+		t.Error("SubmitToChain should reject a player who isn't the holder")
+	}
+	if !gs.SubmitToChain(owner, 0, 0, TurnDraw, "d") {
+		t.Fatal("SubmitToChain rejected a valid submission")
+	}
+	if gs.SubmitToChain(owner, 0, 0, TurnDraw, "d") {
+		t.Error("SubmitToChain should reject a stale round")
+	}
+
+	next, ok := gs.ChainTurn(0)
+	if !ok {
+		t.Fatal("chain 0 should have a next turn")
+	}
+	if next.Round != 1 || next.TurnType != TurnGuess || next.Prompt != "d" {
+		t.Errorf("next turn = %+v, want round 1 guess of %q", next, "d")
+	}
+	// Other chains haven't moved
+	if r := gs.ChainRound(1); r != 0 {
+		t.Errorf("chain 1 round = %d, want 0", r)
+	}
+	if gs.SyncRound() {
+		t.Error("SyncRound should not advance while other chains lag")
+	}
+
+	// The next holder now has two turns waiting: their own chain and chain 0.
+	if n := len(gs.PendingTurns(next.PlayerID)); n != 2 {
+		t.Errorf("PendingTurns(%s) = %d, want 2", next.PlayerID, n)
+	}
+}
+
+func TestAllChainsComplete(t *testing.T) {
+	gs := setupGame(2)
+	for !gs.AllChainsComplete() {
+		progressed := false
+		for ci := range gs.Chains {
+			if info, ok := gs.ChainTurn(ci); ok {
+				gs.SubmitToChain(info.PlayerID, ci, info.Round, info.TurnType, "x")
+				progressed = true
+			}
+		}
+		if !progressed {
+			t.Fatal("no chain could progress")
+		}
+		gs.SyncRound()
+	}
+	if gs.Round != gs.TotalRounds {
+		t.Errorf("Round = %d, want %d", gs.Round, gs.TotalRounds)
+	}
+}
@@ -0,0 +1,316 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+//go:generate go run ../../cmd/protocol-schema -dir protocol
+
+// Wire protocol. Every message payload is one of the structs below, and
+// the JSON Schema in protocol/v{N}.schema.json is generated from them.
+// Any change to a payload needs a ProtocolVersion bump, a fresh schema
+// (go generate) and the new schema's hash in publishedSchemas; the
+// protocol tests fail until all three are done, and whenever a released
+// schema file changes.
+
+// ProtocolVersion is the version of the message payloads below.
+const ProtocolVersion = 5
+
+// MinProtocolVersion is the oldest version clients may still connect with.
+// Versions 2 to 5 only added fields and messages, which ForVersion leaves
+// out for clients that don't know them:
+//
+//	2: game_state carries the receiver's progress through the phase
+//	3: every server message carries Seq; resync
+//	4: presence
+//	5: error carries Code and Type for rejected messages
+const MinProtocolVersion = 1
+
+// NegotiateVersion picks the protocol version for a connection from the
+// one the client asked for. Clients that don't ask get the latest.
+func NegotiateVersion(requested string) (int, error) {
+	if requested == "" {
+		return ProtocolVersion, nil
+	}
+	v, err := strconv.Atoi(requested)
+	if err != nil {
+		return 0, fmt.Errorf("invalid protocol version %q", requested)
+	}
+	if v < MinProtocolVersion || v > ProtocolVersion {
+		return 0, fmt.Errorf("unsupported protocol version %d (server supports %d-%d)", v, MinProtocolVersion, ProtocolVersion)
+	}
+	return v, nil
+}
+
+// messageSince is the version that introduced each server message type
+// added after version 1.
+var messageSince = map[string]int{
+	MsgPresence: 4,
+}
+
+// ForVersion adapts a message for a connection that negotiated an older
+// version, reporting false if the message shouldn't be sent at all. Seq
+// is left in place: it predates nothing a client could misread.
+func ForVersion(msg OutgoingMessage, version int) (OutgoingMessage, bool) {
+	if version < messageSince[msg.Type] {
+		return msg, false
+	}
+	switch data := msg.Data.(type) {
+	case GameStateData:
+		data.ProtocolVersion = version
+		if version < 2 {
+			data = data.withoutProgress()
+		}
+		msg.Data = data
+	case ErrorData:
+		if version < 5 {
+			data.Code, data.Type = "", ""
+			msg.Data = data
+		}
+	}
+	return msg, true
+}
+
+// Server -> Client payloads.
+
+type ErrorData struct {
+	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"`   // set when the message itself was rejected; see ErrCode*
+	Type    string      `json:"type,omitempty"`   // type of the rejected message
+	Errors  []VoteError `json:"errors,omitempty"` // per-field problems with a rejected vote
+}
+
+// Codes for messages rejected before or instead of being handled.
+const (
+	ErrCodeInvalid     = "invalid_message" // not a well-formed message
+	ErrCodeUnknownType = "unknown_type"
+	ErrCodeTooLarge    = "too_large"
+	ErrCodeRateLimited = "rate_limited"
+)
+
+type GameStateData struct {
+	ProtocolVersion int            `json:"protocolVersion"` // the version negotiated for the receiving connection
+	PlayerID        string         `json:"playerId"`        // the receiving player
+	Code            string         `json:"code"`
+	Phase           GamePhase      `json:"phase"`
+	Players         []*Player      `json:"players"`
+	Round           int            `json:"round"`
+	TotalRounds     int            `json:"totalRounds"`
+	HostID          string         `json:"hostId"`
+	Scores          map[string]int `json:"scores"`
+	Settings        Settings       `json:"settings"`
+	Teams           map[string]int `json:"teams"`
+	TeamScores      map[int]int    `json:"teamScores"`
+	Spectators      []*Player      `json:"spectators"`
+	Paused          bool           `json:"paused"`
+	PausedRemaining int            `json:"pausedRemaining"`
+
+	// Progress through the current phase; see Snapshot.
+	Remaining int            `json:"remaining,omitempty"` // seconds left on the round or voting countdown
+	Turn      *TurnStartData `json:"turn,omitempty"`      // the receiver's turn, if they still have one to submit
+	Submitted []string       `json:"submitted,omitempty"` // lockstep: players who have submitted this round
+	Pending   map[string]int `json:"pending,omitempty"`   // per-chain modes: playerID → turns waiting on them
+
+	Chains          []*Chain                      `json:"chains,omitempty"` // reveal only
+	VoteTime        int                           `json:"voteTime,omitempty"`
+	AwardCategories []AwardCategory               `json:"awardCategories,omitempty"`
+	Voted           []string                      `json:"voted,omitempty"` // players who have voted
+	Vote            *PlayerVote                   `json:"vote,omitempty"`  // the receiver's own vote
+	VotingDone      bool                          `json:"votingDone,omitempty"`
+	Awards          map[AwardCategory]AwardWinner `json:"awards,omitempty"`  // once voting is done
+	Winners         []string                      `json:"winners,omitempty"` // once voting is done
+}
+
+// withoutProgress drops the fields version 2 added.
+func (s GameStateData) withoutProgress() GameStateData {
+	s.Remaining, s.Turn, s.Submitted, s.Pending = 0, nil, nil, nil
+	s.Chains, s.VoteTime, s.AwardCategories = nil, 0, nil
+	s.Voted, s.Vote, s.VotingDone, s.Awards, s.Winners = nil, nil, false, nil, nil
+	return s
+}
+
+type PlayerJoinedData struct {
+	Player      *Player `json:"player"`
+	Spectator   bool    `json:"spectator,omitempty"`   // joined mid-game and is watching
+	Round       int     `json:"round,omitempty"`       // seated mid-game from this round
+	TotalRounds int     `json:"totalRounds,omitempty"` // chain length after seating them
+}
+
+type PlayerLeftData struct {
+	PlayerID string  `json:"playerId"`
+	HostID   string  `json:"hostId"`
+	StandIn  *Player `json:"standIn,omitempty"` // bot now holding their seat
+}
+
+type GameStartedData struct{}
+
+type TurnStartData struct {
+	Round       int      `json:"round"`
+	TotalRounds int      `json:"totalRounds"`
+	TurnType    TurnType `json:"turnType"`
+	Prompt      string   `json:"prompt"`
+	TimeLimit   int      `json:"timeLimit"` // seconds
+
+	// Per-chain modes only.
+	ChainIdx *int       `json:"chainIdx,omitempty"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Pending  int        `json:"pending,omitempty"` // turns waiting for this player, this one included
+}
+
+// TickData is the payload of both turn_tick and vote_tick.
+type TickData struct {
+	Remaining int            `json:"remaining"`
+	Hurry     bool           `json:"hurry,omitempty"`   // the round countdown was shortened
+	Players   map[string]int `json:"players,omitempty"` // playerID → seconds left on their own deadline
+}
+
+type WaitingData struct {
+	Pending int `json:"pending"` // per-chain modes: turns queued for this player
+}
+
+type RoundCompleteData struct {
+	Round int `json:"round"`
+}
+
+type GameOverData struct {
+	Chains          []*Chain        `json:"chains"`
+	Scores          map[string]int  `json:"scores"`
+	VoteTime        int             `json:"voteTime"`
+	AwardCategories []AwardCategory `json:"awardCategories"`
+}
+
+type ScoreUpdateData struct {
+	Scores     map[string]int                `json:"scores"`
+	TeamScores map[int]int                   `json:"teamScores"`
+	Awards     map[AwardCategory]AwardWinner `json:"awards"`
+	Winners    []string                      `json:"winners"`
+	VotingDone bool                          `json:"votingDone"`
+}
+
+type SessionSummaryData struct {
+	GamesPlayed int            `json:"gamesPlayed"`
+	Wins        map[string]int `json:"wins"`
+	Scores      map[string]int `json:"scores"`
+}
+
+type ReturnToLobbyData struct {
+	Players    []*Player      `json:"players"`
+	Scores     map[string]int `json:"scores"`
+	TeamScores map[int]int    `json:"teamScores"`
+	HostID     string         `json:"hostId"`
+	Aborted    bool           `json:"aborted,omitempty"` // the host ended the game early
+	Chains     []*Chain       `json:"chains,omitempty"`  // unfinished chains, if the host kept them
+}
+
+type SettingsUpdatedData struct {
+	Settings Settings `json:"settings"`
+}
+
+type TeamsUpdatedData struct {
+	Teams map[string]int `json:"teams"`
+}
+
+type HurryUpData struct {
+	Remaining int    `json:"remaining"`
+	Reason    string `json:"reason"` // "everyone_else_done" or "nudge"
+}
+
+type GamePausedData struct {
+	Remaining int                `json:"remaining"` // seconds left on the turn countdown
+	MaxPause  int                `json:"maxPause"`  // seconds until OnTimeout applies
+	OnTimeout PauseTimeoutAction `json:"onTimeout"`
+}
+
+type GameResumedData struct {
+	Remaining int `json:"remaining"`
+}
+
+type PlayerAwayData struct {
+	PlayerID string `json:"playerId"`
+	Grace    int    `json:"grace"` // seconds until a bot takes their seat
+}
+
+type SeatTakenOverData struct {
+	PlayerID string  `json:"playerId"`
+	HostID   string  `json:"hostId"`
+	StandIn  *Player `json:"standIn"`
+}
+
+type SeatReturnedData struct {
+	PlayerID  string `json:"playerId"`
+	StandInID string `json:"standInId"`
+}
+
+type PresenceData struct {
+	PlayerID string   `json:"playerId"`
+	Presence Presence `json:"presence"`
+}
+
+// serverMessages maps each server message type to its payload.
+var serverMessages = map[string]interface{}{
+	MsgError:           ErrorData{},
+	MsgGameState:       GameStateData{},
+	MsgPlayerJoined:    PlayerJoinedData{},
+	MsgPlayerLeft:      PlayerLeftData{},
+	MsgGameStarted:     GameStartedData{},
+	MsgTurnStart:       TurnStartData{},
+	MsgTurnTick:        TickData{},
+	MsgVoteTick:        TickData{},
+	MsgWaiting:         WaitingData{},
+	MsgRoundComplete:   RoundCompleteData{},
+	MsgGameOver:        GameOverData{},
+	MsgScoreUpdate:     ScoreUpdateData{},
+	MsgSessionSummary:  SessionSummaryData{},
+	MsgReturnToLobby:   ReturnToLobbyData{},
+	MsgSettingsUpdated: SettingsUpdatedData{},
+	MsgTeamsUpdated:    TeamsUpdatedData{},
+	MsgHurryUp:         HurryUpData{},
+	MsgGamePaused:      GamePausedData{},
+	MsgGameResumed:     GameResumedData{},
+	MsgPlayerAway:      PlayerAwayData{},
+	MsgSeatTakenOver:   SeatTakenOverData{},
+	MsgSeatReturned:    SeatReturnedData{},
+	MsgPresence:        PresenceData{},
+}
+
+// clientMessages maps each client message type to its payload; nil means
+// the message carries no data.
+var clientMessages = map[string]interface{}{
+	MsgAddAI:          nil,
+	MsgStartGame:      nil,
+	MsgSubmitDrawing:  submitDrawingData{},
+	MsgSubmitGuess:    submitGuessData{},
+	MsgKickPlayer:     kickData{},
+	MsgSubmitVotes:    submitVotesData{},
+	MsgEndVoting:      nil,
+	MsgPlayAgain:      nil,
+	MsgUpdateSettings: settingsUpdate{},
+	MsgAssignTeam:     assignTeamData{},
+	MsgNudge:          nudgeData{},
+	MsgPauseGame:      nil,
+	MsgResumeGame:     nil,
+	MsgAbortGame:      abortGameData{},
+	MsgResync:         nil,
+}
+
+// Supersedable reports whether a message is made obsolete by the next one
+// of its type, like countdown ticks. Such messages don't advance Seq, so a
+// lagging connection may drop stale ones without leaving a gap.
+func Supersedable(msgType string) bool {
+	return msgType == MsgTurnTick || msgType == MsgVoteTick
+}
+
+// sendError sends an error message to a single player.
+func (g *Game) sendError(playerID, message string) {
+	g.send(playerID, OutgoingMessage{Type: MsgError, Data: ErrorData{Message: message}})
+}
+
+// Reject tells a player one of their messages was refused without being
+// handled, e.g. by the connection's rate limits.
+func (g *Game) Reject(playerID string, e ErrorData) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.send(playerID, OutgoingMessage{Type: MsgError, Data: e})
+}
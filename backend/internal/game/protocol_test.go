@@ -0,0 +1,219 @@
+package game
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// publishedSchemas holds the SHA-256 of every released schema file. A
+// version's schema never changes once recorded here; payload changes need
+// a new version, whose hash gets added when it's generated.
+var publishedSchemas = map[int]string{
+	1: "274d00ba47ba6886ba1de611c9288ec12dec34a95ce5449993f6b1e541d12496",
+	2: "c51f7887b9de2c7afde69312fb32fb5a8e4728b5c4832641d44b5cdc955a00f7",
+	3: "4bba8ca797025997de4a784846612da9b376f13b1480e068ffab21c75647172d",
+	4: "a06c40a3a9f8e4a708e712df2142bfe23fcd73d384a43eff2ed0a0e5d0ba686f",
+	5: "4653c1b27cb0bc0190704bac3d629944e8d5adfbeb1329544f208b6fcbc26885",
+}
+
+func TestProtocolSchema_Frozen(t *testing.T) {
+	for v := MinProtocolVersion; v <= ProtocolVersion; v++ {
+		path := fmt.Sprintf("protocol/v%d.schema.json", v)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("no schema for protocol v%d: %v", v, err)
+			continue
+		}
+		sum := fmt.Sprintf("%x", sha256.Sum256(data))
+		want, ok := publishedSchemas[v]
+		switch {
+		case !ok:
+			t.Errorf("v%d has no recorded hash: add %d: %q to publishedSchemas", v, v, sum)
+		case sum != want:
+			t.Errorf("%s changed after release (sha256 %s, want %s): restore it and bump ProtocolVersion instead", path, sum, want)
+		}
+	}
+}
+
+func TestProtocolSchema(t *testing.T) {
+	got, err := ProtocolSchema()
+	if err != nil {
+		t.Fatalf("ProtocolSchema: %v", err)
+	}
+	path := fmt.Sprintf("protocol/v%d.schema.json", ProtocolVersion)
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("no schema for protocol v%d: run go generate ./internal/game", ProtocolVersion)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("message payloads no longer match %s: bump ProtocolVersion and run go generate ./internal/game", path)
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      int
+		wantErr   bool
+	}{
+		{"", ProtocolVersion, false},
+		{fmt.Sprint(ProtocolVersion), ProtocolVersion, false},
+		{fmt.Sprint(MinProtocolVersion - 1), 0, true},
+		{fmt.Sprint(ProtocolVersion + 1), 0, true},
+		{"latest", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := NegotiateVersion(tt.requested)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NegotiateVersion(%q) = %d, %v; want %d, err %v", tt.requested, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// Every message sent during a full game must carry its registered payload
+// type, so the schema describes what actually goes over the wire.
+func TestProtocol_PayloadsMatchRegistry(t *testing.T) {
+	g, rec := newTestGame(3)
+	g.HandleJoin(NewHumanPlayer("Late"))
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgKickPlayer})
+	playToReveal(t, g)
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgEndVoting})
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgPlayAgain})
+
+	check := func(m OutgoingMessage) {
+		want, ok := serverMessages[m.Type]
+		if !ok {
+			t.Errorf("%s is not a registered server message", m.Type)
+			return
+		}
+		if reflect.TypeOf(m.Data) != reflect.TypeOf(want) {
+			t.Errorf("%s carries %T, want %T", m.Type, m.Data, want)
+		}
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for _, m := range rec.broadcast {
+		check(m)
+	}
+	for _, msgs := range rec.sent {
+		for _, m := range msgs {
+			check(m)
+		}
+	}
+}
+
+func TestSeq_NumbersBroadcastsInOrder(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	p1 := g.State.Players[1].ID
+	g.HandleMessage(p1, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+
+	rec.mu.Lock()
+	var last uint64
+	for i, m := range rec.broadcast {
+		if !Supersedable(m.Type) {
+			last++
+		}
+		if m.Seq != last {
+			t.Errorf("broadcast %d (%s) seq = %d, want %d", i, m.Type, m.Seq, last)
+		}
+	}
+	rec.mu.Unlock()
+
+	g.HandleMessage(p1, IncomingMessage{Type: MsgResync})
+	m := rec.lastSent(p1, MsgGameState)
+	if m == nil {
+		t.Fatal("expected game_state in reply to resync")
+	}
+	if m.Seq != last {
+		t.Errorf("resync seq = %d, want the last broadcast's %d", m.Seq, last)
+	}
+	if s := m.Data.(GameStateData); len(s.Submitted) != 1 || s.Submitted[0] != p1 {
+		t.Errorf("resync snapshot Submitted = %v, want [%s]", s.Submitted, p1)
+	}
+}
+
+func TestHandleMessage_UnknownType(t *testing.T) {
+	g, rec := newTestGame(2)
+	p := g.State.Players[0].ID
+	g.HandleMessage(p, IncomingMessage{Type: "draw_faster"})
+
+	m := rec.lastSent(p, MsgError)
+	if m == nil {
+		t.Fatal("expected an error for an unknown message type")
+	}
+	e := m.Data.(ErrorData)
+	if e.Code != ErrCodeUnknownType || e.Type != "draw_faster" {
+		t.Errorf("error = %+v, want code %q for type draw_faster", e, ErrCodeUnknownType)
+	}
+}
+
+func TestForVersion(t *testing.T) {
+	state := OutgoingMessage{Type: MsgGameState, Data: GameStateData{ProtocolVersion: ProtocolVersion, Remaining: 30}}
+	m, ok := ForVersion(state, 1)
+	if !ok {
+		t.Fatal("game_state dropped for v1")
+	}
+	if s := m.Data.(GameStateData); s.ProtocolVersion != 1 || s.Remaining != 0 {
+		t.Errorf("v1 game_state = version %d, remaining %d; want 1, 0", s.ProtocolVersion, s.Remaining)
+	}
+	if s := state.Data.(GameStateData); s.Remaining != 30 {
+		t.Error("ForVersion modified the original message")
+	}
+	if m, _ := ForVersion(state, 2); m.Data.(GameStateData).Remaining != 30 {
+		t.Error("v2 game_state lost its progress")
+	}
+
+	presence := OutgoingMessage{Type: MsgPresence, Data: PresenceData{PlayerID: "p1", Presence: PresenceIdle}}
+	if _, ok := ForVersion(presence, 3); ok {
+		t.Error("presence sent to a v3 client")
+	}
+	if _, ok := ForVersion(presence, 4); !ok {
+		t.Error("presence withheld from a v4 client")
+	}
+
+	rejected := OutgoingMessage{Type: MsgError, Data: ErrorData{Message: "slow down", Code: ErrCodeRateLimited, Type: MsgNudge}}
+	if m, _ := ForVersion(rejected, 4); !reflect.DeepEqual(m.Data, ErrorData{Message: "slow down"}) {
+		t.Errorf("v4 error = %+v, want only the message", m.Data)
+	}
+}
+
+// Clients send {} or nothing as the data of messages without a payload.
+func TestProtocolSchema_EmptyPayloads(t *testing.T) {
+	raw, err := ProtocolSchema()
+	if err != nil {
+		t.Fatalf("ProtocolSchema: %v", err)
+	}
+	var schema struct {
+		Defs struct {
+			ClientMessage struct {
+				OneOf []struct {
+					Required   []string
+					Properties map[string]map[string]interface{}
+				}
+			}
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	for _, m := range schema.Defs.ClientMessage.OneOf {
+		msgType := m.Properties["type"]["const"]
+		if clientMessages[msgType.(string)] != nil {
+			continue
+		}
+		data := m.Properties["data"]
+		if data["type"] != "object" || data["maxProperties"] != 0.0 {
+			t.Errorf("%s data schema = %v, want an empty object", msgType, data)
+		}
+		for _, r := range m.Required {
+			if r == "data" {
+				t.Errorf("%s requires data", msgType)
+			}
+		}
+	}
+}
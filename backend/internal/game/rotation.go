@@ -0,0 +1,243 @@
+package game
+
+import "math/rand"
+
+// RotationSchedule selects how chains are passed between players each round.
+type RotationSchedule string
+
+const (
+	// RotationNeighbour always passes to the next seat.
+	RotationNeighbour RotationSchedule = "neighbour"
+	// RotationShuffle shuffles seats and hand-off distances so no one passes
+	// to the same player twice where the player count allows it.
+	RotationShuffle RotationSchedule = "shuffle"
+	// RotationFinalGuess passes to the next seat but drops a round when the
+	// round count is odd so every chain ends on a guess.
+	RotationFinalGuess RotationSchedule = "final_guess"
+)
+
+func (s RotationSchedule) valid() bool {
+	switch s {
+	case RotationNeighbour, RotationShuffle, RotationFinalGuess:
+		return true
+	}
+	return false
+}
+
+// Rotation maps rounds to chain assignments. Players sit at positions
+// 0..n-1 (Order[pos] is the player index at that position); in round r the
+// player at position x works on the chain owned by position x-Steps[r].
+// Steps[0] is always 0 so everyone starts on their own chain.
+type Rotation struct {
+	Order []int
+	Steps []int
+	pos   []int // player index → position
+	held  []int // position → chain held when the step is 0
+	at    []int // chain → position holding it when the step is 0
+}
+
+func newRotation(order, steps []int) *Rotation {
+	return newRotationFrom(order, steps, order)
+}
+
+// newRotationFrom is newRotation for a table where the player at position
+// p holds chain held[p], not necessarily their own, when the step is 0.
+func newRotationFrom(order, steps, held []int) *Rotation {
+	pos := make([]int, len(order))
+	for p, idx := range order {
+		pos[idx] = p
+	}
+	at := make([]int, len(held))
+	for p, ci := range held {
+		at[ci] = p
+	}
+	return &Rotation{Order: order, Steps: steps, pos: pos, held: held, at: at}
+}
+
+// grow returns the rotation for a table that gains players mid-game, for
+// rounds from the given one on: next's. Everyone already seated works on
+// the chain rot gives them in that round, the players from index
+// len(rot.Order) on are seated after them and start on their own chains,
+// and from there the chains move on by next's steps. next must be built for
+// the grown table; rounds before from aren't covered.
+func (rot *Rotation) grow(from, rounds int, next *Rotation) *Rotation {
+	n := len(next.Order)
+	order := make([]int, n)
+	held := make([]int, n)
+	copy(order, rot.Order)
+	for p := range order {
+		if p >= len(rot.Order) {
+			order[p] = p
+			held[p] = p
+			continue
+		}
+		held[p] = rot.ChainFor(order[p], from)
+	}
+	steps := make([]int, rounds)
+	for r := from; r < rounds; r++ {
+		steps[r] = next.Steps[r-from]
+	}
+	return newRotationFrom(order, steps, held)
+}
+
+// neighbourRotation is the classic fixed rotation: chain c is held by
+// player c+r in round r, wrapping back to its owner after n rounds.
+func neighbourRotation(n, rounds int) *Rotation {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	steps := make([]int, rounds)
+	for r := range steps {
+		steps[r] = r % n
+	}
+	return newRotation(order, steps)
+}
+
+// shuffleRotation picks a random seating and a step sequence whose
+// round-to-round differences repeat as little as possible. Every distinct
+// difference is a distinct set of hand-off pairs, so for even n (where a
+// repeat-free sequence always exists) nobody passes to the same player
+// twice.
+//
+// When sameTeam is set the players are already seated by team: the seating
+// is kept and, ahead of avoiding repeats, the steps keep hand-offs between
+// teammates to a minimum.
+func shuffleRotation(n, rounds int, sameTeam func(a, b int) bool) *Rotation {
+	var order []int
+	var within []int // hand-off distance → hand-offs between teammates
+	if sameTeam == nil {
+		order = rand.Perm(n)
+	} else {
+		order = make([]int, n)
+		within = make([]int, n)
+		for x := range order {
+			order[x] = x
+			for d := 1; d < n; d++ {
+				if sameTeam(x, (x+d)%n) {
+					within[d]++
+				}
+			}
+		}
+	}
+
+	var best [][]int
+	bestWithin, bestRepeats := -1, -1
+	permute(n-1, func(p []int) {
+		steps := make([]int, n)
+		for i, v := range p {
+			steps[i+1] = v + 1
+		}
+		w := withinTeamHandoffs(steps, n, rounds, within)
+		r := repeatedHandoffs(steps, n)
+		switch {
+		case bestWithin < 0 || w < bestWithin || w == bestWithin && r < bestRepeats:
+			bestWithin, bestRepeats = w, r
+			best = [][]int{steps}
+		case w == bestWithin && r == bestRepeats:
+			best = append(best, steps)
+		}
+	})
+	base := []int{0}
+	if len(best) > 0 {
+		base = best[rand.Intn(len(best))]
+	}
+
+	// Chains longer than the player count repeat the sequence, so players
+	// revisit chains in the same order.
+	steps := make([]int, rounds)
+	for r := range steps {
+		steps[r] = base[r%len(base)]
+	}
+	return newRotation(order, steps)
+}
+
+// withinTeamHandoffs counts the hand-offs between teammates over a step
+// sequence, including the wrap back to its start when rounds outnumber it.
+// within is nil when teams don't matter.
+func withinTeamHandoffs(steps []int, n, rounds int, within []int) int {
+	if within == nil {
+		return 0
+	}
+	count := 0
+	for r := 1; r < len(steps); r++ {
+		count += within[((steps[r]-steps[r-1])%n+n)%n]
+	}
+	if rounds > len(steps) {
+		count += within[((steps[0]-steps[len(steps)-1])%n+n)%n]
+	}
+	return count
+}
+
+// repeatedHandoffs counts how many round-to-round step differences have
+// already been used earlier in the sequence.
+func repeatedHandoffs(steps []int, n int) int {
+	seen := make(map[int]bool)
+	repeats := 0
+	for r := 1; r < len(steps); r++ {
+		d := ((steps[r]-steps[r-1])%n + n) % n
+		if seen[d] {
+			repeats++
+		}
+		seen[d] = true
+	}
+	return repeats
+}
+
+// permute calls fn with every permutation of 0..k-1. fn must not retain p.
+func permute(k int, fn func(p []int)) {
+	p := make([]int, k)
+	for i := range p {
+		p[i] = i
+	}
+	var gen func(i int)
+	gen = func(i int) {
+		if i >= k-1 {
+			fn(p)
+			return
+		}
+		for j := i; j < k; j++ {
+			p[i], p[j] = p[j], p[i]
+			gen(i + 1)
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+	gen(0)
+}
+
+// ChainFor returns the chain index held by the given player in a round.
+func (rot *Rotation) ChainFor(playerIdx, round int) int {
+	n := len(rot.Order)
+	x := rot.pos[playerIdx]
+	return rot.held[((x-rot.Steps[round])%n+n)%n]
+}
+
+// HolderOf returns the player index holding the given chain in a round.
+func (rot *Rotation) HolderOf(chainIdx, round int) int {
+	n := len(rot.Order)
+	x := rot.at[chainIdx]
+	return rot.Order[(x+rot.Steps[round])%n]
+}
+
+// roundsFor returns how many rounds to play with n players: the configured
+// chain length, or one round per player by default. The final-guess
+// schedule trims an odd count by one so chains end on a guess.
+func (s Settings) roundsFor(n int) int {
+	rounds := n
+	if s.Rounds > 0 {
+		rounds = s.Rounds
+	}
+	if s.Rotation == RotationFinalGuess && rounds > 2 && rounds%2 == 1 {
+		rounds--
+	}
+	return rounds
+}
+
+// buildRotation returns the rotation for a schedule. sameTeam, if set,
+// reports whether two seats hold teammates; see shuffleRotation.
+func buildRotation(schedule RotationSchedule, n, rounds int, sameTeam func(a, b int) bool) *Rotation {
+	if schedule == RotationShuffle {
+		return shuffleRotation(n, rounds, sameTeam)
+	}
+	return neighbourRotation(n, rounds)
+}
@@ -0,0 +1,125 @@
+package game
+
+import "testing"
+
+var allSchedules = []RotationSchedule{RotationNeighbour, RotationShuffle, RotationFinalGuess}
+
+// playAssignments collects round → player index → chain index for a game.
+func playAssignments(gs *GameState) [][]int {
+	var rounds [][]int
+	for r := 0; r < gs.TotalRounds; r++ {
+		gs.Round = r
+		row := make([]int, len(gs.Players))
+		for _, p := range gs.Players {
+			row[p.Index], _ = gs.GetAssignment(p.Index)
+		}
+		rounds = append(rounds, row)
+	}
+	gs.Round = 0
+	return rounds
+}
+
+func setupSchedule(schedule RotationSchedule, n int) *GameState {
+	host := NewHumanPlayer("P0")
+	gs := NewGameState("TEST1", host)
+	for i := 1; i < n; i++ {
+		gs.AddPlayer(NewHumanPlayer("P" + string(rune('0'+i))))
+	}
+	gs.Settings.Rotation = schedule
+	gs.InitChains()
+	return gs
+}
+
+func TestRotation_EveryPlayerTouchesChainAtMostOnce(t *testing.T) {
+	for _, schedule := range allSchedules {
+		for n := 2; n <= 8; n++ {
+			gs := setupSchedule(schedule, n)
+			rounds := playAssignments(gs)
+
+			touched := make(map[[2]int]bool) // {player, chain}
+			for r, row := range rounds {
+				held := make(map[int]bool)
+				for p, c := range row {
+					if c < 0 || c >= n {
+						t.Fatalf("%s n=%d round %d: chain %d out of range", schedule, n, r, c)
+					}
+					if held[c] {
+						t.Errorf("%s n=%d round %d: chain %d held by two players", schedule, n, r, c)
+					}
+					held[c] = true
+					if touched[[2]int{p, c}] {
+						t.Errorf("%s n=%d round %d: player %d touches chain %d again", schedule, n, r, p, c)
+					}
+					touched[[2]int{p, c}] = true
+				}
+			}
+			// Everyone starts on their own chain
+			for p, c := range rounds[0] {
+				if c != p {
+					t.Errorf("%s n=%d: player %d starts on chain %d", schedule, n, p, c)
+				}
+			}
+		}
+	}
+}
+
+func TestRotation_HolderOfMatchesChainFor(t *testing.T) {
+	for _, schedule := range allSchedules {
+		gs := setupSchedule(schedule, 6)
+		for r := 0; r < gs.TotalRounds; r++ {
+			for p := 0; p < 6; p++ {
+				c := gs.Rotation.ChainFor(p, r)
+				if h := gs.Rotation.HolderOf(c, r); h != p {
+					t.Errorf("%s round %d: HolderOf(%d) = %d, want %d", schedule, r, c, h, p)
+				}
+			}
+		}
+	}
+}
+
+func TestRotation_NeighbourMatchesClassic(t *testing.T) {
+	gs := setupSchedule(RotationNeighbour, 5)
+	for r, row := range playAssignments(gs) {
+		for p, c := range row {
+			if want := ((p-r)%5 + 5) % 5; c != want {
+				t.Errorf("round %d player %d: chain %d, want %d", r, p, c, want)
+			}
+		}
+	}
+}
+
+func TestRotation_ShuffleAvoidsRepeatedHandoffs(t *testing.T) {
+	for n := 2; n <= 8; n++ {
+		gs := setupSchedule(RotationShuffle, n)
+		rounds := playAssignments(gs)
+
+		pairs := make(map[[2]int]int) // {from, to} → count
+		for r := 1; r < len(rounds); r++ {
+			for p, c := range rounds[r] {
+				from := gs.Rotation.HolderOf(c, r-1)
+				pairs[[2]int{from, p}]++
+			}
+		}
+		repeats := 0
+		for _, count := range pairs {
+			repeats += count - 1
+		}
+		if n%2 == 0 && repeats != 0 {
+			t.Errorf("n=%d: %d repeated hand-offs, want 0", n, repeats)
+		}
+		// Odd cyclic groups can't avoid repeats entirely but should get close.
+		if n%2 == 1 && repeats > n {
+			t.Errorf("n=%d: %d repeated hand-offs, want at most %d", n, repeats, n)
+		}
+	}
+}
+
+func TestRotation_FinalGuessEndsOnGuess(t *testing.T) {
+	for n := 2; n <= 8; n++ {
+		gs := setupSchedule(RotationFinalGuess, n)
+		gs.Round = gs.TotalRounds - 1
+		if _, turnType := gs.GetAssignment(0); turnType != TurnGuess {
+			t.Errorf("n=%d: final round (%d of %d) is not a guess", n, gs.Round+1, gs.TotalRounds)
+		}
+	}
+}
@@ -3,7 +3,8 @@ package game
 // InitChains sets up chains for all players with random words.
 func (gs *GameState) InitChains() {
 	n := gs.PlayerCount()
-	gs.TotalRounds = n
+	gs.TotalRounds = gs.Settings.roundsFor(n)
+	gs.Rotation = buildRotation(gs.Settings.Rotation, n, gs.TotalRounds, gs.teammateSeats())
 	gs.Round = 0
 	words := RandomWords(n)
 	gs.Chains = make([]*Chain, n)
@@ -19,6 +20,7 @@ func (gs *GameState) InitChains() {
 type TurnInfo struct {
 	PlayerID string
 	ChainIdx int
+	Round    int
 	TurnType TurnType
 	Prompt   string // word or drawing data URL to work from
 }
@@ -27,25 +29,13 @@ func (gs *GameState) GetTurnInfos() []TurnInfo {
 	var infos []TurnInfo
 	for _, p := range gs.Players {
 		chainIdx, turnType := gs.GetAssignment(p.Index)
-		chain := gs.Chains[chainIdx]
-
-		var prompt string
-		if gs.Round == 0 {
-			prompt = chain.OriginalWord
-		} else {
-			lastEntry := chain.Entries[len(chain.Entries)-1]
-			if turnType == TurnDraw {
-				prompt = lastEntry.Guess
-			} else {
-				prompt = lastEntry.Drawing
-			}
-		}
 
 		infos = append(infos, TurnInfo{
 			PlayerID: p.ID,
 			ChainIdx: chainIdx,
+			Round:    gs.Round,
 			TurnType: turnType,
-			Prompt:   prompt,
+			Prompt:   chainPrompt(gs.Chains[chainIdx], turnType),
 		})
 	}
 	return infos
@@ -61,7 +51,7 @@ func (gs *GameState) SubmitDrawing(playerID string, drawing string) bool {
 	if turnType != TurnDraw {
 		return false
 	}
-	gs.Chains[chainIdx].Entries = append(gs.Chains[chainIdx].Entries, ChainEntry{
+	gs.Chains[chainIdx].AddEntry(ChainEntry{
 		PlayerID: playerID,
 		Type:     TurnDraw,
 		Drawing:  drawing,
@@ -79,7 +69,7 @@ func (gs *GameState) SubmitGuess(playerID string, guess string) bool {
 	if turnType != TurnGuess {
 		return false
 	}
-	gs.Chains[chainIdx].Entries = append(gs.Chains[chainIdx].Entries, ChainEntry{
+	gs.Chains[chainIdx].AddEntry(ChainEntry{
 		PlayerID: playerID,
 		Type:     TurnGuess,
 		Guess:    guess,
@@ -88,11 +78,14 @@ func (gs *GameState) SubmitGuess(playerID string, guess string) bool {
 }
 
 // AllSubmitted checks if all players have submitted for the current round.
+// Every chain gains exactly one entry per round, however many rounds there
+// are, so round r is complete once each assigned chain has r+1 entries
+// (counting from its StartRound).
 func (gs *GameState) AllSubmitted() bool {
 	for _, p := range gs.Players {
 		chainIdx, _ := gs.GetAssignment(p.Index)
 		chain := gs.Chains[chainIdx]
-		if len(chain.Entries) <= gs.Round {
+		if len(chain.Entries) <= gs.Round-chain.StartRound {
 			return false
 		}
 	}
@@ -129,8 +122,14 @@ func (gs *GameState) HumanPlayers() []*Player {
 func (gs *GameState) ResetForNewGame() {
 	gs.Phase = PhaseLobby
 	gs.Chains = nil
+	gs.Rotation = nil
 	gs.Round = 0
 	gs.TotalRounds = 0
 	gs.Votes = make(map[string]*PlayerVote)
 	gs.VotesSubmitted = make(map[string]bool)
+	gs.VotingDone = false
+	gs.Paused = false
+	gs.PausedRemaining = 0
+	gs.removeStandIns()
+	gs.seatSpectators()
 }
@@ -227,3 +227,129 @@ func TestResetForNewGame(t *testing.T) {
 		t.Errorf("Players len = %d, want 3", len(gs.Players))
 	}
 }
+
+// playRounds fills in every remaining round, advancing until the game ends.
+func playRounds(t *testing.T, gs *GameState) {
+	t.Helper()
+	for {
+		if gs.AllSubmitted() {
+			t.Fatalf("round %d: AllSubmitted before any submissions", gs.Round)
+		}
+		for _, info := range gs.GetTurnInfos() {
+			if info.TurnType == TurnDraw {
+				gs.SubmitDrawing(info.PlayerID, "drawing")
+			} else {
+				gs.SubmitGuess(info.PlayerID, "guess")
+			}
+		}
+		if !gs.AllSubmitted() {
+			t.Fatalf("round %d: AllSubmitted false after every player submitted", gs.Round)
+		}
+		if gs.AdvanceRound() {
+			return
+		}
+	}
+}
+
+func TestInitChains_ConfiguredRounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		players  int
+		rounds   int
+		rotation RotationSchedule
+		want     int
+	}{
+		{"default one per player", 3, 0, RotationNeighbour, 3},
+		{"shorter than players", 6, 2, RotationNeighbour, 2},
+		{"longer than players", 3, 7, RotationNeighbour, 7},
+		{"longer with shuffle", 4, 9, RotationShuffle, 9},
+		{"final guess trims odd", 4, 7, RotationFinalGuess, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := setupSchedule(tt.rotation, tt.players)
+			gs.Settings.Rounds = tt.rounds
+			gs.InitChains()
+			if gs.TotalRounds != tt.want {
+				t.Errorf("TotalRounds = %d, want %d", gs.TotalRounds, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariableRounds_ChainsGrowOnePerRound(t *testing.T) {
+	for _, rounds := range []int{2, 5, 8} {
+		gs := setupGame(3)
+		gs.Settings.Rounds = rounds
+		gs.InitChains()
+
+		playRounds(t, gs)
+
+		if gs.Phase != PhaseReveal {
+			t.Errorf("rounds=%d: Phase = %d, want PhaseReveal", rounds, gs.Phase)
+		}
+		for i, c := range gs.Chains {
+			if len(c.Entries) != rounds {
+				t.Errorf("rounds=%d: chain %d has %d entries, want %d", rounds, i, len(c.Entries), rounds)
+			}
+			for j, e := range c.Entries {
+				want := TurnDraw
+				if j%2 == 1 {
+					want = TurnGuess
+				}
+				if e.Type != want {
+					t.Errorf("rounds=%d: chain %d entry %d type = %d, want %d", rounds, i, j, e.Type, want)
+				}
+			}
+		}
+	}
+}
+
+func TestVariableRounds_PlayersRevisitChains(t *testing.T) {
+	gs := setupGame(2)
+	gs.Settings.Rounds = 4
+	gs.InitChains()
+
+	// With 2 players and 4 rounds each player holds each chain twice.
+	holds := make(map[[2]int]int)
+	for r := 0; r < gs.TotalRounds; r++ {
+		gs.Round = r
+		for _, p := range gs.Players {
+			c, _ := gs.GetAssignment(p.Index)
+			holds[[2]int{p.Index, c}]++
+		}
+	}
+	for k, n := range holds {
+		if n != 2 {
+			t.Errorf("player %d held chain %d %d times, want 2", k[0], k[1], n)
+		}
+	}
+}
+
+func TestGetTurnInfos_LongChainPromptsFollowChain(t *testing.T) {
+	gs := setupGame(2)
+	gs.Settings.Rounds = 4
+	gs.InitChains()
+
+	for gs.Round < gs.TotalRounds {
+		for _, info := range gs.GetTurnInfos() {
+			chain := gs.Chains[info.ChainIdx]
+			if gs.Round > 0 {
+				last := chain.Entries[len(chain.Entries)-1]
+				want := last.Guess
+				if info.TurnType == TurnGuess {
+					want = last.Drawing
+				}
+				if info.Prompt != want {
+					t.Errorf("round %d chain %d: prompt = %q, want %q", gs.Round, info.ChainIdx, info.Prompt, want)
+				}
+			}
+			if info.TurnType == TurnDraw {
+				gs.SubmitDrawing(info.PlayerID, "drawing-r"+string(rune('0'+gs.Round)))
+			} else {
+				gs.SubmitGuess(info.PlayerID, "guess-r"+string(rune('0'+gs.Round)))
+			}
+		}
+		gs.AdvanceRound()
+	}
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProtocolSchema returns the JSON Schema for every message in the current
+// protocol version, generated from the payload structs.
+func ProtocolSchema() ([]byte, error) {
+	b := &schemaBuilder{defs: make(map[string]interface{})}
+	b.defs["ServerMessage"] = b.messages(serverMessages, true)
+	b.defs["ClientMessage"] = b.messages(clientMessages, false)
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "drawl WebSocket protocol",
+		"version": ProtocolVersion,
+		"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/$defs/ServerMessage"},
+			map[string]interface{}{"$ref": "#/$defs/ClientMessage"},
+		},
+		"$defs": b.defs,
+	}
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// schemaBuilder turns Go types into JSON Schema, collecting named struct
+// types under $defs.
+type schemaBuilder struct {
+	defs map[string]interface{}
+}
+
+// messages builds a schema matching any of the given message types, each
+// as a {"type", "data"} envelope around its payload; server messages also
+// carry "seq". Messages without a payload may send an empty object as
+// data, or leave it out.
+func (b *schemaBuilder) messages(payloads map[string]interface{}, server bool) map[string]interface{} {
+	types := make([]string, 0, len(payloads))
+	for msgType := range payloads {
+		types = append(types, msgType)
+	}
+	sort.Strings(types)
+
+	var oneOf []interface{}
+	for _, msgType := range types {
+		data := map[string]interface{}{"type": "object", "maxProperties": 0}
+		if p := payloads[msgType]; p != nil {
+			data = b.schemaFor(reflect.TypeOf(p))
+		}
+		props := map[string]interface{}{
+			"type": map[string]interface{}{"const": msgType},
+			"data": data,
+		}
+		required := []string{"type"}
+		if server {
+			props["seq"] = map[string]interface{}{"type": "integer", "minimum": 0}
+			required = append(required, "seq")
+		}
+		oneOf = append(oneOf, map[string]interface{}{
+			"type":       "object",
+			"required":   required,
+			"properties": props,
+		})
+	}
+	return map[string]interface{}{"oneOf": oneOf}
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schemaFor(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.defs[t.Name()] = nil // placeholder for recursive types
+			b.defs[t.Name()] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{} // interface{}: any value
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	b.addFields(t, props, &required)
+	sort.Strings(required)
+	s := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds t's JSON fields to props, flattening embedded structs the
+// way encoding/json does. Fields that are neither omitempty nor pointers
+// are required.
+func (b *schemaBuilder) addFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.addFields(f.Type, props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schemaFor(f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
+		}
+	}
+}
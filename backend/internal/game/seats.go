@@ -0,0 +1,257 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// Mid-game departures. Seat indices drive every chain assignment, so once
+// a game is under way a departing player's seat is handed to an AI
+// stand-in instead of being removed. The stand-in plays through the AI
+// handler, or submits placeholders when none is configured, and leaves
+// when the game returns to the lobby.
+//
+// With bot takeover enabled a disconnected player keeps their seat for a
+// grace period first, and gets it back from the bot if they reconnect.
+
+// ReplaceWithStandIn swaps a player for an AI stand-in at the same seat.
+// Returns the stand-in, or nil if the player isn't seated.
+func (gs *GameState) ReplaceWithStandIn(id string) *Player {
+	for i, p := range gs.Players {
+		if p.ID != id {
+			continue
+		}
+		s := NewAIPlayer()
+		s.Name = p.Name + " (bot)"
+		s.Index = p.Index
+		s.StandInFor = p.ID
+		gs.Players[i] = s
+
+		// The departed player stays on their team as well: their chain
+		// keeps their ID as owner, and its points still count for it.
+		if team, ok := gs.Teams[id]; ok {
+			gs.Teams[s.ID] = team
+		}
+		delete(gs.Settings.PlayerTurnTimes, id)
+		gs.promoteHost(id)
+		return s
+	}
+	return nil
+}
+
+// removeStandIns drops every stand-in, and the team of the player it stood
+// in for, and re-indexes the remaining seats.
+func (gs *GameState) removeStandIns() {
+	var kept []*Player
+	for _, p := range gs.Players {
+		if p.StandInFor == "" {
+			p.Index = len(kept)
+			kept = append(kept, p)
+		} else {
+			delete(gs.Teams, p.ID)
+			delete(gs.Teams, p.StandInFor)
+		}
+	}
+	gs.Players = kept
+}
+
+// removePlayer takes a player out of the game, seating a stand-in while a
+// game is being played, and tells everyone.
+func (g *Game) removePlayer(p *Player) {
+	if g.State.Phase == PhasePlaying {
+		s := g.seatStandIn(p)
+		g.broadcast(OutgoingMessage{Type: MsgPlayerLeft, Data: PlayerLeftData{
+			PlayerID: p.ID,
+			HostID:   g.State.HostID,
+			StandIn:  s,
+		}})
+		return
+	}
+
+	if g.State.Phase == PhaseReveal && !g.State.VotesSubmitted[p.ID] {
+		g.State.VotesSubmitted[p.ID] = true
+		g.State.Votes[p.ID] = &PlayerVote{}
+		g.checkAllVotesIn()
+	}
+	g.State.RemovePlayer(p.ID)
+	g.broadcast(OutgoingMessage{Type: MsgPlayerLeft, Data: PlayerLeftData{
+		PlayerID: p.ID,
+		HostID:   g.State.HostID,
+	}})
+}
+
+// seatStandIn hands a departing player's seat, and any turns waiting on
+// it, to a new stand-in.
+func (g *Game) seatStandIn(p *Player) *Player {
+	s := g.State.ReplaceWithStandIn(p.ID)
+	log.Printf("[game %s] %s left mid-game, %s stands in at seat %d", g.State.Code, p.Name, s.Name, s.Index)
+
+	if t := g.playerTimers[p.ID]; t != nil {
+		t.Stop()
+		delete(g.playerTimers, p.ID)
+	}
+	delete(g.playerDeadlines, p.ID)
+
+	switch g.State.Settings.Mode {
+	case ModeSync:
+		if g.submitted[p.ID] {
+			g.submitted[s.ID] = true
+			return s
+		}
+		chainIdx, turnType := g.State.GetAssignment(s.Index)
+		go g.handleAITurn(TurnInfo{
+			PlayerID: s.ID,
+			ChainIdx: chainIdx,
+			Round:    g.State.Round,
+			TurnType: turnType,
+			Prompt:   chainPrompt(g.State.Chains[chainIdx], turnType),
+		})
+	case ModePipeline:
+		g.queues[s.ID] = g.queues[p.ID]
+		delete(g.queues, p.ID)
+		g.serveTurn(s.ID)
+	default:
+		for _, info := range g.State.PendingTurns(s.ID) {
+			g.offerChainTurn(info.ChainIdx)
+		}
+	}
+	return s
+}
+
+// ReturnSeat puts a player back in the seat their stand-in holds. Returns
+// the stand-in, or nil if there is none.
+func (gs *GameState) ReturnSeat(p *Player) *Player {
+	for i, s := range gs.Players {
+		if s.StandInFor != p.ID {
+			continue
+		}
+		p.Index = s.Index
+		gs.Players[i] = p
+		if team, ok := gs.Teams[s.ID]; ok {
+			gs.Teams[p.ID] = team
+		}
+		delete(gs.Teams, s.ID)
+		return s
+	}
+	return nil
+}
+
+// PlayerByToken returns the player with the given token, including one
+// whose seat a bot is holding.
+func (g *Game) PlayerByToken(token string) *Player {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if p := g.State.FindPlayerByToken(token); p != nil {
+		return p
+	}
+	for _, p := range g.takenOver {
+		if p.Token == token {
+			return p
+		}
+	}
+	return nil
+}
+
+// startGrace keeps a disconnected player's seat for the grace period
+// before a bot takes it over.
+func (g *Game) startGrace(p *Player) {
+	grace := time.Duration(g.State.Settings.TakeoverGraceSeconds) * time.Second
+	var t *time.Timer
+	t = time.AfterFunc(grace, func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.graceTimers[p.ID] != t {
+			return // reconnected while waiting for the lock
+		}
+		g.graceExpired(p.ID)
+	})
+	g.graceTimers[p.ID] = t
+	log.Printf("[game %s] %s disconnected, holding seat for %s", g.State.Code, p.Name, grace)
+	g.broadcast(OutgoingMessage{Type: MsgPlayerAway, Data: PlayerAwayData{
+		PlayerID: p.ID,
+		Grace:    g.State.Settings.TakeoverGraceSeconds,
+	}})
+}
+
+// graceExpired hands a still-disconnected player's seat to a bot, or
+// removes them if the game is no longer being played.
+func (g *Game) graceExpired(playerID string) {
+	delete(g.graceTimers, playerID)
+	p := g.State.FindPlayer(playerID)
+	if p == nil {
+		return
+	}
+	if g.State.Phase != PhasePlaying {
+		g.removePlayer(p)
+		return
+	}
+	s := g.seatStandIn(p)
+	g.takenOver[p.ID] = p
+	g.broadcast(OutgoingMessage{Type: MsgSeatTakenOver, Data: SeatTakenOverData{
+		PlayerID: p.ID,
+		HostID:   g.State.HostID,
+		StandIn:  s,
+	}})
+}
+
+// HandleReconnect is called when a player opens a new connection, and
+// sends them the game state. A player still inside their grace period
+// carries on; one whose seat a bot took over gets it back, along with any
+// turn the bot hasn't finished.
+func (g *Game) HandleReconnect(playerID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	defer g.sendGameState(playerID)
+	defer g.setPresence(playerID, PresenceOnline)
+
+	if t := g.graceTimers[playerID]; t != nil {
+		t.Stop()
+		delete(g.graceTimers, playerID)
+		log.Printf("[game %s] player %s reconnected within grace period", g.State.Code, playerID)
+		return
+	}
+	p := g.takenOver[playerID]
+	if p == nil {
+		return
+	}
+	delete(g.takenOver, playerID)
+	s := g.State.ReturnSeat(p)
+	if s == nil {
+		return
+	}
+	log.Printf("[game %s] %s reconnected, taking their seat back from %s", g.State.Code, p.Name, s.Name)
+	g.broadcast(OutgoingMessage{Type: MsgSeatReturned, Data: SeatReturnedData{
+		PlayerID:  p.ID,
+		StandInID: s.ID,
+	}})
+
+	if g.State.Phase == PhaseReveal {
+		// The stand-in only has the empty vote every bot gets, so the
+		// player casts their own. Once voting is done the votes belong to
+		// the game's history record and stay as they are.
+		if !g.State.VotingDone {
+			delete(g.State.VotesSubmitted, s.ID)
+			delete(g.State.Votes, s.ID)
+		}
+		return
+	}
+	if g.State.Phase != PhasePlaying {
+		return
+	}
+	switch g.State.Settings.Mode {
+	case ModeSync:
+		if g.submitted[s.ID] {
+			g.submitted[p.ID] = true
+			return
+		}
+		for _, info := range g.State.GetTurnInfos() {
+			if info.PlayerID == p.ID {
+				g.send(p.ID, OutgoingMessage{Type: MsgTurnStart, Data: g.turnData(info, g.secondsLeft())})
+			}
+		}
+	case ModePipeline:
+		g.queues[p.ID] = g.queues[s.ID]
+		delete(g.queues, s.ID)
+		g.serveTurn(p.ID)
+	}
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+// waitFor polls cond under the game lock until it holds or two seconds pass.
+func waitFor(t *testing.T, g *Game, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		ok := cond()
+		g.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met in time")
+}
+
+// assertChainAuthors checks every entry was written by the player seated
+// where the rotation sends that chain in that round.
+func assertChainAuthors(t *testing.T, g *Game, seats []*Player) {
+	t.Helper()
+	for ci, c := range g.State.Chains {
+		for r, e := range c.Entries {
+			want := seats[g.State.Rotation.HolderOf(ci, r)]
+			if e.PlayerID != want.ID {
+				t.Errorf("chain %d round %d written by %s, want %s", ci, r, e.PlayerID, want.ID)
+			}
+		}
+	}
+}
+
+func TestKick_MidGameKeepsSeats(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	p0, p1, p2 := g.State.Players[0], g.State.Players[1], g.State.Players[2]
+
+	g.HandleMessage(p0.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d0"}))
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d1"}))
+	g.HandleMessage(p0.ID, msg(t, MsgKickPlayer, kickData{PlayerID: p2.ID}))
+
+	g.mu.Lock()
+	s := g.State.playerAt(2)
+	if s == nil || s.StandInFor != p2.ID || s.Type != AIPlayer {
+		t.Fatalf("seat 2 = %+v, want an AI stand-in for %s", s, p2.ID)
+	}
+	if p0.Index != 0 || p1.Index != 1 {
+		t.Errorf("seat indices shifted: p0=%d p1=%d", p0.Index, p1.Index)
+	}
+	if m := rec.lastBroadcast(MsgPlayerLeft); m == nil || m.Data.(PlayerLeftData).StandIn != s {
+		t.Error("expected player_left with the stand-in")
+	}
+	g.mu.Unlock()
+
+	// The stand-in takes the kicked player's turns; the others keep
+	// writing into the chains the rotation gives them.
+	waitFor(t, g, func() bool { return g.State.Round == 1 && g.State.Phase == PhasePlaying && g.timer != nil })
+	g.HandleMessage(p0.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "g0"}))
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitGuess, submitGuessData{Guess: "g1"}))
+	waitFor(t, g, func() bool { return g.State.Round == 2 })
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	assertChainAuthors(t, g, []*Player{p0, p1, s})
+}
+
+func TestDisconnect_MidGameKeepsSeats(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 3)
+	p0, p1 := g.State.Players[0], g.State.Players[1]
+
+	g.HandleDisconnect(p0.ID)
+	if g.State.HostID != p1.ID {
+		t.Errorf("HostID = %s, want %s", g.State.HostID, p1.ID)
+	}
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d1"}))
+	g.HandleMessage(g.State.Players[2].ID, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "d2"}))
+	waitFor(t, g, func() bool { return g.State.Round == 1 })
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := g.State.playerAt(0)
+	if s == nil || s.StandInFor != p0.ID {
+		t.Fatalf("seat 0 = %+v, want a stand-in for %s", s, p0.ID)
+	}
+	assertChainAuthors(t, g, []*Player{s, p1, g.State.Players[2]})
+}
+
+func TestDisconnect_LobbyStillRemoves(t *testing.T) {
+	g, _ := newTestGame(3)
+	p1 := g.State.Players[1]
+
+	g.HandleDisconnect(p1.ID)
+	if g.State.PlayerCount() != 2 {
+		t.Errorf("PlayerCount = %d, want 2", g.State.PlayerCount())
+	}
+	if g.State.Players[1].Index != 1 {
+		t.Error("lobby seats should be re-indexed")
+	}
+}
+
+func TestStandIns_LeaveAfterGame(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 3)
+	p1 := g.State.Players[1]
+	g.HandleDisconnect(p1.ID)
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgAbortGame})
+	if g.State.PlayerCount() != 2 {
+		t.Fatalf("PlayerCount = %d, want 2", g.State.PlayerCount())
+	}
+	for i, p := range g.State.Players {
+		if p.StandInFor != "" || p.Index != i {
+			t.Errorf("seat %d = %+v, want a re-indexed original player", i, p)
+		}
+	}
+}
+
+// botTakeover enables bot takeover for newModeGame.
+func botTakeover(s *GameState) { s.Settings.BotTakeover = true }
+
+func TestBotTakeover_GracePeriod(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3, botTakeover)
+	p1 := g.State.Players[1]
+
+	g.HandleDisconnect(p1.ID)
+	if g.State.FindPlayer(p1.ID) == nil {
+		t.Fatal("player should keep their seat during the grace period")
+	}
+	if rec.lastBroadcast(MsgPlayerAway) == nil {
+		t.Error("expected player_away broadcast")
+	}
+
+	g.HandleReconnect(p1.ID)
+	if g.graceTimers[p1.ID] != nil {
+		t.Error("reconnecting should cancel the takeover")
+	}
+	if rec.lastSent(p1.ID, MsgGameState) == nil {
+		t.Error("expected game_state on reconnect")
+	}
+}
+
+func TestBotTakeover_HandsSeatBack(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3, botTakeover)
+	p1 := g.State.Players[1]
+
+	g.HandleDisconnect(p1.ID)
+	g.mu.Lock()
+	g.graceExpired(p1.ID)
+	s := g.State.playerAt(1)
+	g.mu.Unlock()
+
+	if s == nil || s.StandInFor != p1.ID || s.Type != AIPlayer {
+		t.Fatalf("seat 1 = %+v, want a bot standing in for %s", s, p1.ID)
+	}
+	if s.Name != p1.Name+" (bot)" {
+		t.Errorf("stand-in name = %q, want %q", s.Name, p1.Name+" (bot)")
+	}
+	if rec.lastBroadcast(MsgSeatTakenOver) == nil {
+		t.Error("expected seat_taken_over broadcast")
+	}
+	if g.PlayerByToken(p1.Token) != p1 {
+		t.Fatal("departed player should still be found by token")
+	}
+
+	// The bot finishes the round before the player comes back.
+	waitFor(t, g, func() bool { return g.submitted[s.ID] })
+	g.HandleReconnect(p1.ID)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.State.playerAt(1) != p1 || p1.Index != 1 {
+		t.Fatalf("seat 1 = %+v, want %s back", g.State.playerAt(1), p1.ID)
+	}
+	if !g.submitted[p1.ID] {
+		t.Error("the bot's submission should count for the returning player")
+	}
+	if rec.lastBroadcast(MsgSeatReturned) == nil {
+		t.Error("expected seat_returned broadcast")
+	}
+}
+
+func TestBotTakeover_ReturnMidTurn(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3, botTakeover)
+	p1 := g.State.Players[1]
+
+	g.HandleDisconnect(p1.ID)
+	g.mu.Lock()
+	g.ai = &blockingAI{started: make(chan struct{}, 1)} // the bot's turn doesn't land until cancelled
+	g.graceExpired(p1.ID)
+	s := g.State.playerAt(1)
+	g.mu.Unlock()
+	g.HandleReconnect(p1.ID)
+
+	g.mu.Lock()
+	g.submitAITurn(TurnInfo{PlayerID: s.ID, ChainIdx: 1, Round: 0, TurnType: TurnDraw}, "bot")
+	g.mu.Unlock()
+	if g.submitted[s.ID] {
+		t.Error("a stand-in that handed its seat back should not submit")
+	}
+	if rec.lastSent(p1.ID, MsgTurnStart) == nil {
+		t.Error("returning player should get their turn back")
+	}
+}
+
+// toRevealWithStandIn plays a takeover game with p1's seat taken over by a
+// bot through to the reveal.
+func toRevealWithStandIn(t *testing.T) (*Game, *Player, *Player) {
+	t.Helper()
+	g, _ := newModeGame(t, ModeSync, 3, botTakeover)
+	p1 := g.State.Players[1]
+	g.HandleDisconnect(p1.ID)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.graceExpired(p1.ID)
+	s := g.State.playerAt(1)
+	for g.State.Phase == PhasePlaying {
+		g.forceSubmitAll()
+		g.checkRoundComplete()
+		g.submitted = make(map[string]bool)
+	}
+	if g.State.Phase != PhaseReveal {
+		t.Fatalf("Phase = %d, want PhaseReveal", g.State.Phase)
+	}
+	return g, p1, s
+}
+
+func TestBotTakeover_ReturnDuringVoting(t *testing.T) {
+	g, p1, s := toRevealWithStandIn(t)
+	g.HandleReconnect(p1.ID)
+	if g.State.VotesSubmitted[p1.ID] || g.State.VotesSubmitted[s.ID] {
+		t.Fatal("the returning player should vote for themselves, not inherit the bot's vote")
+	}
+
+	g.HandleMessage(p1.ID, msg(t, MsgSubmitVotes, submitVotesData{SuccessChains: []int{0}}))
+	if v := g.State.Votes[p1.ID]; v == nil || len(v.SuccessChains) != 1 || v.SuccessChains[0] != 0 {
+		t.Errorf("vote = %+v, want the player's own", v)
+	}
+}
+
+func TestBotTakeover_ReturnAfterVoting(t *testing.T) {
+	g, p1, s := toRevealWithStandIn(t)
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgEndVoting})
+	if !g.State.VotingDone {
+		t.Fatal("voting should be done")
+	}
+	votes := g.State.History[len(g.State.History)-1].Votes
+	g.HandleReconnect(p1.ID)
+	if _, ok := votes[s.ID]; !ok {
+		t.Error("the history record's votes changed after voting was done")
+	}
+}
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// GameMode selects how turns are paced.
+type GameMode string
+
+const (
+	// ModeSync plays lockstep rounds against a shared per-turn timer.
+	ModeSync GameMode = "sync"
+	// ModeAsync is play-by-post: each chain advances as soon as its next
+	// player submits, with deadlines measured in hours.
+	ModeAsync GameMode = "async"
+	// ModePipeline advances each chain independently at normal speed:
+	// players get their next turn as soon as it reaches them and they're
+	// free, rather than waiting for the whole table.
+	ModePipeline GameMode = "pipeline"
+)
+
+func (m GameMode) valid() bool {
+	switch m {
+	case ModeSync, ModeAsync, ModePipeline:
+		return true
+	}
+	return false
+}
+
+// MaxAsyncDeadlineHours caps the per-turn deadline in async mode.
+const MaxAsyncDeadlineHours = 72
+
+// Settings are host-configurable game options, changed in the lobby.
+type Settings struct {
+	Mode               GameMode `json:"mode"`
+	AsyncDeadlineHours int      `json:"asyncDeadlineHours"` // per-turn deadline in async mode
+
+	TeamMode         bool             `json:"teamMode"`
+	CrossTeamHandoff bool             `json:"crossTeamHandoff"` // seat players so every neighbour hand-off crosses teams
+	Rotation         RotationSchedule `json:"rotation"`
+	Rounds           int              `json:"rounds"` // chain length; 0 means one round per player
+
+	PlayerTurnTimes map[string]int `json:"playerTurnTimes,omitempty"` // playerID → own turn time in seconds
+	HurrySeconds    int            `json:"hurrySeconds"`              // shortened countdown once most have submitted; 0 disables
+	HurryWhenLeft   int            `json:"hurryWhenLeft"`             // how few players must remain to shorten it
+
+	MaxPauseMinutes int                `json:"maxPauseMinutes"` // how long the host can pause before PauseTimeout applies
+	PauseTimeout    PauseTimeoutAction `json:"pauseTimeout"`
+
+	LateJoin LateJoinMode `json:"lateJoin"` // what happens to players joining a running game
+
+	BotTakeover          bool `json:"botTakeover"`          // a bot takes over a disconnected player's seat, handing it back if they return
+	TakeoverGraceSeconds int  `json:"takeoverGraceSeconds"` // how long a seat waits before the bot takes over
+}
+
+// MaxRounds caps the configurable chain length.
+const MaxRounds = 16
+
+// Bounds for per-player turn times and the hurry-up countdown, in seconds.
+const (
+	MinPlayerTurnTime = 10
+	MaxPlayerTurnTime = 600
+	MaxHurrySeconds   = 60
+)
+
+// Bounds for the bot takeover grace period, in seconds.
+const (
+	MinTakeoverGrace = 5
+	MaxTakeoverGrace = 300
+)
+
+// settingsUpdate is a partial Settings; nil fields are left unchanged.
+type settingsUpdate struct {
+	Mode               *GameMode `json:"mode"`
+	AsyncDeadlineHours *int      `json:"asyncDeadlineHours"`
+
+	TeamMode         *bool             `json:"teamMode"`
+	CrossTeamHandoff *bool             `json:"crossTeamHandoff"`
+	Rotation         *RotationSchedule `json:"rotation"`
+	Rounds           *int              `json:"rounds"`
+
+	PlayerTurnTimes map[string]int `json:"playerTurnTimes,omitempty"` // merged; 0 clears a player's override
+	HurrySeconds    *int           `json:"hurrySeconds"`
+	HurryWhenLeft   *int           `json:"hurryWhenLeft"`
+
+	MaxPauseMinutes *int                `json:"maxPauseMinutes"`
+	PauseTimeout    *PauseTimeoutAction `json:"pauseTimeout"`
+
+	LateJoin *LateJoinMode `json:"lateJoin"`
+
+	BotTakeover          *bool `json:"botTakeover"`
+	TakeoverGraceSeconds *int  `json:"takeoverGraceSeconds"`
+}
+
+// apply validates the whole update before changing anything.
+func (s *Settings) apply(u settingsUpdate) error {
+	if u.Mode != nil && !u.Mode.valid() {
+		return errors.New("unknown game mode")
+	}
+	if u.AsyncDeadlineHours != nil && (*u.AsyncDeadlineHours < 1 || *u.AsyncDeadlineHours > MaxAsyncDeadlineHours) {
+		return fmt.Errorf("async deadline must be between 1 and %d hours", MaxAsyncDeadlineHours)
+	}
+	if u.Rotation != nil && !u.Rotation.valid() {
+		return errors.New("unknown rotation schedule")
+	}
+	if u.Rounds != nil && *u.Rounds != 0 && (*u.Rounds < 2 || *u.Rounds > MaxRounds) {
+		return fmt.Errorf("rounds must be between 2 and %d", MaxRounds)
+	}
+	for _, t := range u.PlayerTurnTimes {
+		if t != 0 && (t < MinPlayerTurnTime || t > MaxPlayerTurnTime) {
+			return fmt.Errorf("turn time must be between %d and %d seconds", MinPlayerTurnTime, MaxPlayerTurnTime)
+		}
+	}
+	if u.HurrySeconds != nil && (*u.HurrySeconds < 0 || *u.HurrySeconds > MaxHurrySeconds) {
+		return fmt.Errorf("hurry countdown must be between 0 and %d seconds", MaxHurrySeconds)
+	}
+	if u.HurryWhenLeft != nil && (*u.HurryWhenLeft < 1 || *u.HurryWhenLeft > 2) {
+		return errors.New("hurry threshold must be 1 or 2 players")
+	}
+	if u.MaxPauseMinutes != nil && (*u.MaxPauseMinutes < 1 || *u.MaxPauseMinutes > MaxPauseMinutes) {
+		return fmt.Errorf("max pause must be between 1 and %d minutes", MaxPauseMinutes)
+	}
+	if u.PauseTimeout != nil && !u.PauseTimeout.valid() {
+		return errors.New("unknown pause timeout action")
+	}
+	if u.LateJoin != nil && !u.LateJoin.valid() {
+		return errors.New("unknown late join mode")
+	}
+	if u.TakeoverGraceSeconds != nil && (*u.TakeoverGraceSeconds < MinTakeoverGrace || *u.TakeoverGraceSeconds > MaxTakeoverGrace) {
+		return fmt.Errorf("takeover grace must be between %d and %d seconds", MinTakeoverGrace, MaxTakeoverGrace)
+	}
+
+	if u.Mode != nil {
+		s.Mode = *u.Mode
+	}
+	if u.AsyncDeadlineHours != nil {
+		s.AsyncDeadlineHours = *u.AsyncDeadlineHours
+	}
+	if u.TeamMode != nil {
+		s.TeamMode = *u.TeamMode
+	}
+	if u.CrossTeamHandoff != nil {
+		s.CrossTeamHandoff = *u.CrossTeamHandoff
+	}
+	if u.Rotation != nil {
+		s.Rotation = *u.Rotation
+	}
+	if u.Rounds != nil {
+		s.Rounds = *u.Rounds
+	}
+	for id, t := range u.PlayerTurnTimes {
+		if t == 0 {
+			delete(s.PlayerTurnTimes, id)
+			continue
+		}
+		if s.PlayerTurnTimes == nil {
+			s.PlayerTurnTimes = make(map[string]int)
+		}
+		s.PlayerTurnTimes[id] = t
+	}
+	if u.HurrySeconds != nil {
+		s.HurrySeconds = *u.HurrySeconds
+	}
+	if u.HurryWhenLeft != nil {
+		s.HurryWhenLeft = *u.HurryWhenLeft
+	}
+	if u.MaxPauseMinutes != nil {
+		s.MaxPauseMinutes = *u.MaxPauseMinutes
+	}
+	if u.PauseTimeout != nil {
+		s.PauseTimeout = *u.PauseTimeout
+	}
+	if u.LateJoin != nil {
+		s.LateJoin = *u.LateJoin
+	}
+	if u.BotTakeover != nil {
+		s.BotTakeover = *u.BotTakeover
+	}
+	if u.TakeoverGraceSeconds != nil {
+		s.TakeoverGraceSeconds = *u.TakeoverGraceSeconds
+	}
+	return nil
+}
+
+// asyncDeadline returns the async per-turn deadline in seconds.
+func (s Settings) asyncDeadline() int {
+	return s.AsyncDeadlineHours * 60 * 60
+}
+
+func (g *Game) handleUpdateSettings(playerID string, data json.RawMessage) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can change settings")
+		return
+	}
+	if g.State.Phase != PhaseLobby {
+		g.sendError(playerID, "settings can only change in the lobby")
+		return
+	}
+	var u settingsUpdate
+	if err := json.Unmarshal(data, &u); err != nil {
+		g.sendError(playerID, "invalid data")
+		return
+	}
+	if err := g.State.Settings.apply(u); err != nil {
+		g.sendError(playerID, err.Error())
+		return
+	}
+	log.Printf("[game %s] settings updated: %+v", g.State.Code, g.State.Settings)
+	g.broadcast(OutgoingMessage{Type: MsgSettingsUpdated, Data: SettingsUpdatedData{
+		Settings: g.State.Settings,
+	}})
+}
@@ -0,0 +1,122 @@
+package game
+
+import "time"
+
+// Snapshots: a game_state carries everything a client needs to render the
+// current phase from scratch — the receiver's own turn and time left,
+// who has submitted, and reveal and voting progress — so a client that
+// missed messages can always recover from a single game_state.
+
+// Snapshot returns the game as one player sees it.
+func (g *Game) Snapshot(playerID string) GameStateData {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.snapshot(playerID)
+}
+
+func (g *Game) snapshot(playerID string) GameStateData {
+	s := GameStateData{
+		ProtocolVersion: ProtocolVersion,
+		PlayerID:        playerID,
+		Code:            g.State.Code,
+		Phase:           g.State.Phase,
+		Players:         g.State.Players,
+		Round:           g.State.Round,
+		TotalRounds:     g.State.TotalRounds,
+		HostID:          g.State.HostID,
+		Scores:          g.State.Scores,
+		Settings:        g.State.Settings,
+		Teams:           g.State.Teams,
+		TeamScores:      g.State.TeamScores,
+		Spectators:      g.State.Spectators,
+		Paused:          g.State.Paused,
+		PausedRemaining: g.State.PausedRemaining,
+	}
+
+	switch g.State.Phase {
+	case PhasePlaying:
+		if g.State.Settings.Mode == ModeSync {
+			s.Remaining = g.countdownLeft()
+			for _, p := range g.State.Players {
+				if g.submitted[p.ID] {
+					s.Submitted = append(s.Submitted, p.ID)
+				}
+			}
+		} else {
+			s.Pending = make(map[string]int)
+			for _, p := range g.State.Players {
+				if n := len(g.State.PendingTurns(p.ID)); n > 0 {
+					s.Pending[p.ID] = n
+				}
+			}
+		}
+		s.Turn = g.snapshotTurn(playerID)
+
+	case PhaseReveal:
+		s.Chains = g.State.GetChains()
+		s.VoteTime = g.voteTime()
+		s.AwardCategories = AwardCategories
+		s.Vote = g.State.Votes[playerID]
+		for _, p := range g.State.Players {
+			if g.State.VotesSubmitted[p.ID] {
+				s.Voted = append(s.Voted, p.ID)
+			}
+		}
+		if !g.State.VotingDone {
+			s.Remaining = g.countdownLeft()
+			break
+		}
+		s.VotingDone = true
+		s.Awards = g.State.TallyAwards()
+		if n := len(g.State.History); n > 0 {
+			s.Winners = g.State.History[n-1].Winners
+		}
+	}
+	return s
+}
+
+// countdownLeft returns the seconds left on the running countdown, or on
+// the frozen one while paused.
+func (g *Game) countdownLeft() int {
+	if g.paused != nil {
+		return g.State.PausedRemaining
+	}
+	if g.timer == nil {
+		return 0
+	}
+	return g.secondsLeft()
+}
+
+// snapshotTurn returns the turn a player still has to submit, if any.
+func (g *Game) snapshotTurn(playerID string) *TurnStartData {
+	if g.State.Settings.Mode == ModeSync {
+		if g.submitted[playerID] {
+			return nil
+		}
+		for _, info := range g.State.GetTurnInfos() {
+			if info.PlayerID != playerID {
+				continue
+			}
+			left := g.playerSecondsLeft(playerID)
+			if g.paused != nil {
+				left = g.State.PausedRemaining
+				if d, ok := g.paused.players[playerID]; ok && d < g.paused.countdown {
+					left = int((d + time.Second - 1) / time.Second)
+				}
+			}
+			t := g.turnData(info, left)
+			return &t
+		}
+		return nil
+	}
+
+	turns := g.State.PendingTurns(playerID)
+	if g.State.Settings.Mode == ModePipeline {
+		turns = g.currentTurn(playerID)
+	}
+	if len(turns) == 0 {
+		return nil
+	}
+	t := g.chainTurnData(turns[0])
+	return &t
+}
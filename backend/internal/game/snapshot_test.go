@@ -0,0 +1,94 @@
+package game
+
+import "testing"
+
+func TestSnapshot_SyncTurnAndSubmissions(t *testing.T) {
+	g, rec := newModeGame(t, ModeSync, 3)
+	p0, p1 := g.State.Players[0].ID, g.State.Players[1].ID
+
+	g.HandleMessage(p0, msg(t, MsgSubmitDrawing, submitDrawingData{Drawing: "a"}))
+
+	s := g.Snapshot(p1)
+	if s.PlayerID != p1 || s.Phase != PhasePlaying {
+		t.Fatalf("snapshot = %+v", s)
+	}
+	if s.Turn == nil {
+		t.Fatal("expected p1's turn in the snapshot")
+	}
+	if s.Turn.Prompt != g.State.Chains[1].OriginalWord || s.Turn.TurnType != TurnDraw {
+		t.Errorf("turn = %+v, want drawing %q", s.Turn, g.State.Chains[1].OriginalWord)
+	}
+	if s.Remaining < g.State.TurnTime-1 || s.Turn.TimeLimit < g.State.TurnTime-1 {
+		t.Errorf("remaining = %d, timeLimit = %d, want ~%d", s.Remaining, s.Turn.TimeLimit, g.State.TurnTime)
+	}
+	if len(s.Submitted) != 1 || s.Submitted[0] != p0 {
+		t.Errorf("Submitted = %v, want [%s]", s.Submitted, p0)
+	}
+	if g.Snapshot(p0).Turn != nil {
+		t.Error("a player who submitted should have no turn")
+	}
+
+	// The game_state sent on request is the same snapshot.
+	g.mu.Lock()
+	g.sendGameState(p1)
+	g.mu.Unlock()
+	if m := rec.lastSent(p1, MsgGameState); m == nil || m.Data.(GameStateData).Turn == nil {
+		t.Error("game_state should carry the snapshot")
+	}
+}
+
+func TestSnapshot_Paused(t *testing.T) {
+	g, _ := newModeGame(t, ModeSync, 2)
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgPauseGame})
+
+	s := g.Snapshot(g.State.Players[1].ID)
+	if !s.Paused || s.Remaining != s.PausedRemaining {
+		t.Errorf("remaining = %d, want the frozen %d", s.Remaining, s.PausedRemaining)
+	}
+	if s.Turn == nil || s.Turn.TimeLimit != s.PausedRemaining {
+		t.Errorf("turn = %+v, want time limit %d", s.Turn, s.PausedRemaining)
+	}
+}
+
+func TestSnapshot_PipelinePending(t *testing.T) {
+	g, _ := newModeGame(t, ModePipeline, 3)
+	id := g.State.Players[0].ID
+
+	s := g.Snapshot(id)
+	if s.Turn == nil || s.Turn.ChainIdx == nil || *s.Turn.ChainIdx != 0 {
+		t.Fatalf("turn = %+v, want chain 0", s.Turn)
+	}
+	if s.Pending[id] != 1 {
+		t.Errorf("Pending = %v, want 1 for %s", s.Pending, id)
+	}
+	if s.Submitted != nil {
+		t.Error("per-chain modes have no round submissions")
+	}
+}
+
+func TestSnapshot_RevealProgress(t *testing.T) {
+	g, _ := newTestGame(3)
+	playToReveal(t, g)
+	voter := g.State.Players[1].ID
+	g.HandleMessage(voter, msg(t, MsgSubmitVotes, submitVotesData{SuccessChains: []int{2}}))
+
+	s := g.Snapshot(voter)
+	if len(s.Chains) != 3 || len(s.AwardCategories) == 0 || s.Turn != nil {
+		t.Errorf("reveal snapshot = %+v", s)
+	}
+	if len(s.Voted) != 1 || s.Voted[0] != voter {
+		t.Errorf("Voted = %v, want [%s]", s.Voted, voter)
+	}
+	if s.Vote == nil || len(s.Vote.SuccessChains) != 1 {
+		t.Errorf("Vote = %+v, want the voter's own vote", s.Vote)
+	}
+	if g.Snapshot(g.State.Players[2].ID).Vote != nil {
+		t.Error("other players' votes must not be shared")
+	}
+
+	g.HandleMessage(g.State.HostID, IncomingMessage{Type: MsgEndVoting})
+	s = g.Snapshot(voter)
+	if !s.VotingDone || s.Awards == nil || s.Remaining != 0 {
+		t.Errorf("after voting: done=%v awards=%v remaining=%d", s.VotingDone, s.Awards, s.Remaining)
+	}
+}
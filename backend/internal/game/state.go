@@ -1,5 +1,7 @@
 package game
 
+import "github.com/google/uuid"
+
 type GamePhase int
 
 const (
@@ -16,6 +18,7 @@ const (
 )
 
 type ChainEntry struct {
+	ID       string   `json:"id"` // stable within the game, used for vote references
 	PlayerID string   `json:"playerId"`
 	Type     TurnType `json:"type"`
 	Drawing  string   `json:"drawing,omitempty"` // base64 PNG data URL
@@ -26,11 +29,34 @@ type Chain struct {
 	OriginalWord string       `json:"originalWord"`
 	OwnerID      string       `json:"ownerId"`
 	Entries      []ChainEntry `json:"entries"`
+	StartRound   int          `json:"startRound,omitempty"` // round of the first entry; non-zero for late joiners' chains
+}
+
+// AddEntry appends an entry to the chain, assigning it a fresh ID.
+func (c *Chain) AddEntry(e ChainEntry) {
+	e.ID = uuid.New().String()[:8]
+	c.Entries = append(c.Entries, e)
+}
+
+// FindEntry returns the entry with the given ID, or nil.
+func (c *Chain) FindEntry(id string) *ChainEntry {
+	for i := range c.Entries {
+		if c.Entries[i].ID == id {
+			return &c.Entries[i]
+		}
+	}
+	return nil
+}
+
+// EntryRef points at a single chain entry.
+type EntryRef struct {
+	ChainIdx int    `json:"chainIdx"`
+	EntryID  string `json:"entryId"`
 }
 
 type PlayerVote struct {
-	SuccessChains []int  `json:"successChains"` // chain indices player thinks succeeded
-	FavDrawing    string `json:"favDrawing"`    // "chainIdx:entryIdx" of favourite drawing
+	SuccessChains []int                      `json:"successChains"` // chain indices player thinks succeeded
+	Awards        map[AwardCategory]EntryRef `json:"awards"`        // category → picked entry
 }
 
 type GameState struct {
@@ -38,14 +64,28 @@ type GameState struct {
 	Phase       GamePhase `json:"phase"`
 	Players     []*Player `json:"players"`
 	Chains      []*Chain  `json:"-"`
+	Rotation    *Rotation `json:"-"` // fixed at InitChains
 	Round       int       `json:"round"`
 	TotalRounds int       `json:"totalRounds"`
 	HostID      string    `json:"hostId"`
 	TurnTime    int       `json:"turnTime"` // seconds per turn
+	VoteTime    int       `json:"voteTime"` // seconds allowed for reveal voting
 
 	Scores         map[string]int         `json:"scores"`  // playerID → total points
 	Votes          map[string]*PlayerVote `json:"-"`        // playerID → their votes at reveal
 	VotesSubmitted map[string]bool        `json:"-"`        // tracks who has voted
+	VotingDone     bool                   `json:"votingDone"` // scores for this game have been tallied
+
+	Paused          bool `json:"paused"`          // host has stopped the clock
+	PausedRemaining int  `json:"pausedRemaining"` // seconds left on the turn countdown when paused
+
+	History []*GameRecord `json:"-"` // completed games this session, oldest first
+
+	Settings   Settings       `json:"settings"`
+	Teams      map[string]int `json:"teams"`      // playerID → team number, 1..MaxTeams
+	TeamScores map[int]int    `json:"teamScores"` // team → points from team-mode games
+
+	Spectators []*Player `json:"spectators"` // joined mid-game; seated next game or at a round boundary
 }
 
 func NewGameState(code string, host *Player) *GameState {
@@ -56,9 +96,13 @@ func NewGameState(code string, host *Player) *GameState {
 		Players:         []*Player{host},
 		HostID:          host.ID,
 		TurnTime:        60,
+		VoteTime:        90,
 		Scores:         make(map[string]int),
 		Votes:          make(map[string]*PlayerVote),
 		VotesSubmitted: make(map[string]bool),
+		Settings:       Settings{Mode: ModeSync, AsyncDeadlineHours: 24, Rotation: RotationNeighbour, HurrySeconds: 15, HurryWhenLeft: 2, MaxPauseMinutes: 5, PauseTimeout: PauseTimeoutResume, LateJoin: LateJoinSpectate, TakeoverGraceSeconds: 30},
+		Teams:          make(map[string]int),
+		TeamScores:     make(map[int]int),
 	}
 }
 
@@ -74,12 +118,19 @@ func (gs *GameState) RemovePlayer(id string) {
 			break
 		}
 	}
+	delete(gs.Teams, id)
+	delete(gs.Settings.PlayerTurnTimes, id)
 	// Re-index
 	for i, p := range gs.Players {
 		p.Index = i
 	}
-	// Promote host if needed
-	if gs.HostID == id && len(gs.Players) > 0 {
+	gs.promoteHost(id)
+}
+
+// promoteHost hands the host role to the first human if the departing
+// player was host.
+func (gs *GameState) promoteHost(departedID string) {
+	if gs.HostID == departedID && len(gs.Players) > 0 {
 		for _, p := range gs.Players {
 			if p.Type == HumanPlayer {
 				gs.HostID = p.ID
@@ -104,6 +155,11 @@ func (gs *GameState) FindPlayerByToken(token string) *Player {
 			return p
 		}
 	}
+	for _, p := range gs.Spectators {
+		if p.Token == token {
+			return p
+		}
+	}
 	return nil
 }
 
@@ -115,9 +171,8 @@ func (gs *GameState) PlayerCount() int {
 func (gs *GameState) GetAssignment(playerIdx int) (int, TurnType) {
 	n := len(gs.Players)
 	chainIdx := ((playerIdx - gs.Round) % n + n) % n
-	turnType := TurnDraw
-	if gs.Round%2 == 1 {
-		turnType = TurnGuess
+	if gs.Rotation != nil {
+		chainIdx = gs.Rotation.ChainFor(playerIdx, gs.Round)
 	}
-	return chainIdx, turnType
+	return chainIdx, roundTurnType(gs.Round)
 }
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// MaxTeams is the highest team number a player can be assigned to.
+const MaxTeams = 4
+
+// SetTeam assigns a player to a team (1..MaxTeams); team 0 unassigns them.
+func (gs *GameState) SetTeam(playerID string, team int) bool {
+	if gs.FindPlayer(playerID) == nil || team < 0 || team > MaxTeams {
+		return false
+	}
+	if team == 0 {
+		delete(gs.Teams, playerID)
+	} else {
+		gs.Teams[playerID] = team
+	}
+	return true
+}
+
+// TeamsReady reports whether every player is on a team and at least two
+// teams are in use.
+func (gs *GameState) TeamsReady() bool {
+	used := make(map[int]bool)
+	for _, p := range gs.Players {
+		team, ok := gs.Teams[p.ID]
+		if !ok {
+			return false
+		}
+		used[team] = true
+	}
+	return len(used) >= 2
+}
+
+// TeamPoints sums per-player points into per-team totals.
+func (gs *GameState) TeamPoints(points map[string]int) map[int]int {
+	totals := make(map[int]int)
+	for id, p := range points {
+		if team, ok := gs.Teams[id]; ok {
+			totals[team] += p
+		}
+	}
+	return totals
+}
+
+// TeamMembers returns the IDs of players currently on the given team.
+func (gs *GameState) TeamMembers(team int) []string {
+	var ids []string
+	for _, p := range gs.Players {
+		if gs.Teams[p.ID] == team {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
+
+// SeatByTeam reorders players so neighbouring seats — and therefore every
+// chain hand-off — alternate teams wherever team sizes allow it. Largest
+// teams fill the even seats first, then the odd seats.
+func (gs *GameState) SeatByTeam() {
+	byTeam := make(map[int][]*Player)
+	var teams []int
+	for _, p := range gs.Players {
+		t := gs.Teams[p.ID]
+		if _, ok := byTeam[t]; !ok {
+			teams = append(teams, t)
+		}
+		byTeam[t] = append(byTeam[t], p)
+	}
+	sort.SliceStable(teams, func(i, j int) bool {
+		return len(byTeam[teams[i]]) > len(byTeam[teams[j]])
+	})
+
+	n := len(gs.Players)
+	seated := make([]*Player, n)
+	seat := 0
+	for _, t := range teams {
+		for _, p := range byTeam[t] {
+			seated[seat] = p
+			seat += 2
+			if seat >= n {
+				seat = 1
+			}
+		}
+	}
+	for i, p := range seated {
+		p.Index = i
+	}
+	gs.Players = seated
+}
+
+// teammateSeats reports whether two seats hold teammates when players are
+// seated for cross-team hand-offs, and is nil otherwise.
+func (gs *GameState) teammateSeats() func(a, b int) bool {
+	if !gs.Settings.TeamMode || !gs.Settings.CrossTeamHandoff {
+		return nil
+	}
+	return func(a, b int) bool {
+		return gs.Teams[gs.Players[a].ID] == gs.Teams[gs.Players[b].ID]
+	}
+}
+
+type assignTeamData struct {
+	PlayerID string `json:"playerId"`
+	Team     int    `json:"team"`
+}
+
+func (g *Game) handleAssignTeam(playerID string, data json.RawMessage) {
+	if playerID != g.State.HostID {
+		g.sendError(playerID, "only host can assign teams")
+		return
+	}
+	if g.State.Phase != PhaseLobby {
+		g.sendError(playerID, "teams can only change in the lobby")
+		return
+	}
+	var d assignTeamData
+	if err := json.Unmarshal(data, &d); err != nil {
+		g.sendError(playerID, "invalid data")
+		return
+	}
+	if !g.State.SetTeam(d.PlayerID, d.Team) {
+		g.sendError(playerID, "invalid team assignment")
+		return
+	}
+	g.broadcast(OutgoingMessage{Type: MsgTeamsUpdated, Data: TeamsUpdatedData{
+		Teams: g.State.Teams,
+	}})
+}
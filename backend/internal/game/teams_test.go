@@ -0,0 +1,152 @@
+package game
+
+import "testing"
+
+func assignTeams(gs *GameState, teams ...int) {
+	for i, t := range teams {
+		gs.SetTeam(gs.Players[i].ID, t)
+	}
+}
+
+func TestSetTeam(t *testing.T) {
+	gs := setupGame(2)
+	id := gs.Players[1].ID
+
+	if !gs.SetTeam(id, 2) || gs.Teams[id] != 2 {
+		t.Errorf("SetTeam(%s, 2) failed, Teams = %v", id, gs.Teams)
+	}
+	if !gs.SetTeam(id, 0) {
+		t.Error("SetTeam(0) should unassign")
+	}
+	if _, ok := gs.Teams[id]; ok {
+		t.Error("player should no longer have a team")
+	}
+	if gs.SetTeam(id, MaxTeams+1) {
+		t.Error("SetTeam should reject out-of-range team")
+	}
+	if gs.SetTeam("nonexistent", 1) {
+		t.Error("SetTeam should reject unknown player")
+	}
+}
+
+func TestTeamsReady(t *testing.T) {
+	gs := setupGame(3)
+	assignTeams(gs, 1, 1)
+	if gs.TeamsReady() {
+		t.Error("TeamsReady should be false with an unassigned player")
+	}
+	gs.SetTeam(gs.Players[2].ID, 1)
+	if gs.TeamsReady() {
+		t.Error("TeamsReady should be false with a single team")
+	}
+	gs.SetTeam(gs.Players[2].ID, 2)
+	if !gs.TeamsReady() {
+		t.Error("TeamsReady should be true with everyone on 2 teams")
+	}
+}
+
+func TestRemovePlayer_ClearsTeam(t *testing.T) {
+	gs := setupGame(2)
+	id := gs.Players[1].ID
+	gs.SetTeam(id, 1)
+	gs.RemovePlayer(id)
+	if _, ok := gs.Teams[id]; ok {
+		t.Error("removed player should be dropped from Teams")
+	}
+}
+
+func TestSeatByTeam_HandoffsCrossTeams(t *testing.T) {
+	tests := []struct {
+		name  string
+		teams []int
+	}{
+		{"two even teams", []int{1, 1, 1, 2, 2, 2}},
+		{"three teams", []int{1, 1, 2, 2, 3, 3}},
+		{"odd count", []int{1, 1, 2, 2, 3}},
+		{"with AI seats", []int{2, 1, 2, 1}},
+	}
+	for _, schedule := range allSchedules {
+		for _, tt := range tests {
+			t.Run(string(schedule)+"/"+tt.name, func(t *testing.T) {
+				gs := setupGame(len(tt.teams))
+				assignTeams(gs, tt.teams...)
+				gs.Settings.TeamMode = true
+				gs.Settings.CrossTeamHandoff = true
+				gs.Settings.Rotation = schedule
+
+				gs.SeatByTeam()
+				gs.InitChains()
+
+				n := len(gs.Players)
+				for i, p := range gs.Players {
+					if p.Index != i {
+						t.Fatalf("player at seat %d has Index %d", i, p.Index)
+					}
+				}
+				// Whoever holds a chain in round r hands it to whoever holds it in r+1.
+				for r := 0; r < gs.TotalRounds-1; r++ {
+					holder := make(map[int]string)
+					gs.Round = r
+					for _, p := range gs.Players {
+						c, _ := gs.GetAssignment(p.Index)
+						holder[c] = p.ID
+					}
+					gs.Round = r + 1
+					for _, p := range gs.Players {
+						c, _ := gs.GetAssignment(p.Index)
+						if gs.Teams[holder[c]] == gs.Teams[p.ID] {
+							t.Errorf("round %d→%d: chain %d stays on team %d", r, r+1, c, gs.Teams[p.ID])
+						}
+					}
+				}
+				if len(gs.Players) != n {
+					t.Errorf("Players len = %d, want %d", len(gs.Players), n)
+				}
+			})
+		}
+	}
+}
+
+func TestRecordGame_TeamWinners(t *testing.T) {
+	gs := setupGame(4)
+	gs.Settings.TeamMode = true
+	assignTeams(gs, 1, 2, 1, 2)
+	p := gs.Players
+
+	// Team 2 wins on total even though p[0] has the top individual score.
+	rec := gs.RecordGame(map[string]int{p[0].ID: 3, p[1].ID: 2, p[3].ID: 2}, nil)
+
+	if rec.TeamPoints[1] != 3 || rec.TeamPoints[2] != 4 {
+		t.Errorf("TeamPoints = %v, want 1:3 2:4", rec.TeamPoints)
+	}
+	if len(rec.Winners) != 2 {
+		t.Fatalf("Winners = %v, want both team 2 members", rec.Winners)
+	}
+	for _, id := range rec.Winners {
+		if gs.Teams[id] != 2 {
+			t.Errorf("winner %s is on team %d, want 2", id, gs.Teams[id])
+		}
+	}
+}
+
+// A player who leaves mid-game still scores for their team through the
+// chain they own.
+func TestTeamPoints_DepartedPlayerStillCounts(t *testing.T) {
+	gs := setupGame(4)
+	gs.Settings.TeamMode = true
+	assignTeams(gs, 1, 2, 1, 2)
+	gone := gs.Players[1].ID
+
+	s := gs.ReplaceWithStandIn(gone)
+	if gs.Teams[s.ID] != 2 {
+		t.Errorf("stand-in is on team %d, want 2", gs.Teams[s.ID])
+	}
+	if got := gs.TeamPoints(map[string]int{gone: 2, gs.Players[3].ID: 1}); got[2] != 3 {
+		t.Errorf("team 2 points = %d, want 3 including the departed player's chain", got[2])
+	}
+
+	gs.removeStandIns()
+	if _, ok := gs.Teams[gone]; ok {
+		t.Error("the departed player's team should be cleared once stand-ins leave")
+	}
+}
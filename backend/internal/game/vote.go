@@ -0,0 +1,135 @@
+package game
+
+import "fmt"
+
+// AwardCategory is a reveal-phase award players vote on.
+type AwardCategory string
+
+const (
+	AwardFunniest          AwardCategory = "funniest"
+	AwardBestArt           AwardCategory = "best_art"
+	AwardLostInTranslation AwardCategory = "lost_in_translation"
+)
+
+// AwardCategories lists every category in display order.
+var AwardCategories = []AwardCategory{AwardFunniest, AwardBestArt, AwardLostInTranslation}
+
+// drawingOnly reports whether a category only accepts drawing entries.
+func (c AwardCategory) drawingOnly() bool {
+	return c == AwardBestArt
+}
+
+func (c AwardCategory) valid() bool {
+	for _, cat := range AwardCategories {
+		if c == cat {
+			return true
+		}
+	}
+	return false
+}
+
+// AwardWinner is the entry that received the most votes in a category.
+type AwardWinner struct {
+	EntryRef
+	PlayerID string `json:"playerId"`
+	Votes    int    `json:"votes"`
+}
+
+// VoteError describes one rejected part of a vote submission.
+type VoteError struct {
+	Field  string      `json:"field"`
+	Value  interface{} `json:"value"`
+	Reason string      `json:"reason"`
+}
+
+// ValidateVote checks a player's vote against the current chains. Duplicate
+// chain indices are dropped; anything else invalid is reported and the
+// returned vote is nil.
+func (gs *GameState) ValidateVote(playerID string, successChains []int, awards map[AwardCategory]EntryRef) (*PlayerVote, []VoteError) {
+	var errs []VoteError
+
+	seen := make(map[int]bool)
+	chains := make([]int, 0, len(successChains))
+	for _, ci := range successChains {
+		if ci < 0 || ci >= len(gs.Chains) {
+			errs = append(errs, VoteError{Field: "successChains", Value: ci, Reason: "chain index out of range"})
+			continue
+		}
+		if seen[ci] {
+			continue
+		}
+		seen[ci] = true
+		chains = append(chains, ci)
+	}
+
+	picks := make(map[AwardCategory]EntryRef, len(awards))
+	for cat, ref := range awards {
+		field := fmt.Sprintf("awards.%s", cat)
+		if !cat.valid() {
+			errs = append(errs, VoteError{Field: field, Value: cat, Reason: "unknown award category"})
+			continue
+		}
+		if reason := gs.checkAwardPick(playerID, cat, ref); reason != "" {
+			errs = append(errs, VoteError{Field: field, Value: ref, Reason: reason})
+			continue
+		}
+		picks[cat] = ref
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return &PlayerVote{SuccessChains: chains, Awards: picks}, nil
+}
+
+// checkAwardPick returns why an award pick is invalid, or "" if it's fine.
+func (gs *GameState) checkAwardPick(playerID string, cat AwardCategory, ref EntryRef) string {
+	if ref.ChainIdx < 0 || ref.ChainIdx >= len(gs.Chains) {
+		return "chain index out of range"
+	}
+	entry := gs.Chains[ref.ChainIdx].FindEntry(ref.EntryID)
+	if entry == nil {
+		return "entry not found in chain"
+	}
+	if cat.drawingOnly() && entry.Type != TurnDraw {
+		return "entry is not a drawing"
+	}
+	if entry.PlayerID == playerID {
+		return "cannot vote for your own entry"
+	}
+	return ""
+}
+
+// TallyAwards counts award votes and returns the winner of each category
+// that received any. Ties go to the earliest entry in chain order.
+func (gs *GameState) TallyAwards() map[AwardCategory]AwardWinner {
+	counts := make(map[AwardCategory]map[EntryRef]int)
+	for _, vote := range gs.Votes {
+		for cat, ref := range vote.Awards {
+			if counts[cat] == nil {
+				counts[cat] = make(map[EntryRef]int)
+			}
+			counts[cat][ref]++
+		}
+	}
+
+	winners := make(map[AwardCategory]AwardWinner)
+	for _, cat := range AwardCategories {
+		if counts[cat] == nil {
+			continue
+		}
+		var best AwardWinner
+		for ci, chain := range gs.Chains {
+			for _, e := range chain.Entries {
+				ref := EntryRef{ChainIdx: ci, EntryID: e.ID}
+				if n := counts[cat][ref]; n > best.Votes {
+					best = AwardWinner{EntryRef: ref, PlayerID: e.PlayerID, Votes: n}
+				}
+			}
+		}
+		if best.Votes > 0 {
+			winners[cat] = best
+		}
+	}
+	return winners
+}
@@ -0,0 +1,156 @@
+package game
+
+import "testing"
+
+// setupReveal returns a 3-player state with every round filled in.
+func setupReveal() *GameState {
+	gs := setupGame(3)
+	for gs.Round < gs.TotalRounds {
+		for _, p := range gs.Players {
+			_, turnType := gs.GetAssignment(p.Index)
+			if turnType == TurnDraw {
+				gs.SubmitDrawing(p.ID, "drawing-by-"+p.ID)
+			} else {
+				gs.SubmitGuess(p.ID, "guess-by-"+p.ID)
+			}
+		}
+		gs.AdvanceRound()
+	}
+	return gs
+}
+
+func TestValidateVote_DedupesChains(t *testing.T) {
+	gs := setupReveal()
+	vote, errs := gs.ValidateVote(gs.Players[0].ID, []int{1, 1, 2, 1}, nil)
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(vote.SuccessChains) != 2 || vote.SuccessChains[0] != 1 || vote.SuccessChains[1] != 2 {
+		t.Errorf("SuccessChains = %v, want [1 2]", vote.SuccessChains)
+	}
+}
+
+func TestValidateVote_ChainOutOfRange(t *testing.T) {
+	gs := setupReveal()
+	vote, errs := gs.ValidateVote(gs.Players[0].ID, []int{0, 3, -1}, nil)
+	if vote != nil {
+		t.Error("vote should be rejected")
+	}
+	if len(errs) != 2 {
+		t.Fatalf("errs len = %d, want 2", len(errs))
+	}
+	for _, e := range errs {
+		if e.Field != "successChains" {
+			t.Errorf("Field = %q, want successChains", e.Field)
+		}
+	}
+}
+
+func TestValidateVote_Awards(t *testing.T) {
+	gs := setupReveal()
+	voter := gs.Players[0].ID
+
+	// Entry 0 of every chain is a drawing, entry 1 a guess; find drawings by
+	// the voter and by someone else.
+	var own, other EntryRef
+	for ci, c := range gs.Chains {
+		ref := EntryRef{ChainIdx: ci, EntryID: c.Entries[0].ID}
+		if c.Entries[0].PlayerID == voter {
+			own = ref
+		} else {
+			other = ref
+		}
+	}
+	var guess EntryRef
+	for ci, c := range gs.Chains {
+		if c.Entries[1].PlayerID != voter {
+			guess = EntryRef{ChainIdx: ci, EntryID: c.Entries[1].ID}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cat     AwardCategory
+		ref     EntryRef
+		wantErr bool
+	}{
+		{"best art drawing", AwardBestArt, other, false},
+		{"funniest guess", AwardFunniest, guess, false},
+		{"lost in translation guess", AwardLostInTranslation, guess, false},
+		{"own entry", AwardFunniest, own, true},
+		{"best art guess", AwardBestArt, guess, true},
+		{"chain out of range", AwardBestArt, EntryRef{ChainIdx: 9, EntryID: other.EntryID}, true},
+		{"entry not in chain", AwardBestArt, EntryRef{ChainIdx: other.ChainIdx, EntryID: "nope"}, true},
+		{"unknown category", AwardCategory("prettiest"), other, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vote, errs := gs.ValidateVote(voter, nil, map[AwardCategory]EntryRef{tt.cat: tt.ref})
+			if (errs != nil) != tt.wantErr {
+				t.Fatalf("ValidateVote(%s, %+v) errs = %v, wantErr %v", tt.cat, tt.ref, errs, tt.wantErr)
+			}
+			if errs != nil {
+				if want := "awards." + string(tt.cat); errs[0].Field != want {
+					t.Errorf("Field = %q, want %q", errs[0].Field, want)
+				}
+				return
+			}
+			if vote.Awards[tt.cat] != tt.ref {
+				t.Errorf("Awards[%s] = %+v, want %+v", tt.cat, vote.Awards[tt.cat], tt.ref)
+			}
+		})
+	}
+}
+
+func TestChainEntryIDs_Unique(t *testing.T) {
+	gs := setupReveal()
+	seen := make(map[string]bool)
+	for _, c := range gs.Chains {
+		for _, e := range c.Entries {
+			if e.ID == "" {
+				t.Fatal("entry has empty ID")
+			}
+			if seen[e.ID] {
+				t.Errorf("duplicate entry ID %q", e.ID)
+			}
+			seen[e.ID] = true
+		}
+	}
+}
+
+func TestTallyAwards(t *testing.T) {
+	gs := setupReveal()
+	a := EntryRef{ChainIdx: 0, EntryID: gs.Chains[0].Entries[0].ID}
+	b := EntryRef{ChainIdx: 1, EntryID: gs.Chains[1].Entries[0].ID}
+	gs.Votes = map[string]*PlayerVote{
+		"v1": {Awards: map[AwardCategory]EntryRef{AwardBestArt: a, AwardFunniest: b}},
+		"v2": {Awards: map[AwardCategory]EntryRef{AwardBestArt: b, AwardFunniest: b}},
+		"v3": {Awards: map[AwardCategory]EntryRef{AwardBestArt: b}},
+	}
+
+	winners := gs.TallyAwards()
+
+	if w := winners[AwardBestArt]; w.EntryRef != b || w.Votes != 2 || w.PlayerID != gs.Chains[1].Entries[0].PlayerID {
+		t.Errorf("best_art winner = %+v, want %+v with 2 votes", w, b)
+	}
+	if w := winners[AwardFunniest]; w.EntryRef != b || w.Votes != 2 {
+		t.Errorf("funniest winner = %+v, want %+v with 2 votes", w, b)
+	}
+	if _, ok := winners[AwardLostInTranslation]; ok {
+		t.Error("lost_in_translation should have no winner")
+	}
+}
+
+func TestTallyAwards_TieGoesToEarliest(t *testing.T) {
+	gs := setupReveal()
+	a := EntryRef{ChainIdx: 0, EntryID: gs.Chains[0].Entries[1].ID}
+	b := EntryRef{ChainIdx: 1, EntryID: gs.Chains[1].Entries[0].ID}
+	gs.Votes = map[string]*PlayerVote{
+		"v1": {Awards: map[AwardCategory]EntryRef{AwardFunniest: b}},
+		"v2": {Awards: map[AwardCategory]EntryRef{AwardFunniest: a}},
+	}
+
+	if w := gs.TallyAwards()[AwardFunniest]; w.EntryRef != a {
+		t.Errorf("funniest winner = %+v, want %+v", w.EntryRef, a)
+	}
+}
@@ -0,0 +1,25 @@
+// Package httputil holds the request and response helpers shared by the
+// REST API and the event stream transport.
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// PlayerToken returns the player token a request carries, from an
+// Authorization: Bearer header or else the token query parameter.
+func PlayerToken(r *http.Request) string {
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(auth)
+	}
+	return r.URL.Query().Get("token")
+}
+
+// Error writes a JSON { "error": msg } response with the given status.
+func Error(w http.ResponseWriter, msg string, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -3,91 +3,194 @@ package ws
 import (
 	"context"
 	"drawl/internal/game"
+	"errors"
+	"log"
 	"sync"
+	"time"
 
 	"nhooyr.io/websocket"
 )
 
+var (
+	errClientClosed = errors.New("client closed")
+	errSlowConsumer = errors.New("client too far behind")
+)
+
+// Outbound limits. A client whose queue fills up, or whose socket doesn't
+// take a write within writeTimeout, is disconnected; it can reconnect and
+// pick up from a fresh game_state.
+const (
+	maxQueue     = 256
+	writeTimeout = 10 * time.Second
+)
+
+// Client is one player's connection, over a WebSocket or an event stream.
+// Outgoing messages go through a bounded queue drained by a single writer
+// goroutine, so they arrive in the order they were sent and a stalled
+// socket never blocks the sender.
 type Client struct {
+	ID       uint64 // distinguishes a player's connections; set by ClientRegistry.Add
 	PlayerID string
 	GameCode string
-	conn     *websocket.Conn
-	mu       sync.Mutex
+	Version  int   // negotiated protocol version; messages are adapted to it
+	Codec    Codec // encoding for outgoing messages; set before the client is registered
+	out      transport
+	act      *activity // shared by a player's mirrored connections
+
+	inMu    sync.Mutex // serializes inbound messages, which can arrive concurrently over HTTP
+	limiter *inboundLimiter
+
+	mu        sync.Mutex
+	queue     []frame       // waiting to be written, oldest first
+	wake      chan struct{} // signals the writer that the queue is non-empty
+	done      chan struct{} // closed by Close or eviction
+	closeOnce sync.Once
+}
+
+// frame is an encoded message waiting in a client's queue.
+type frame struct {
+	msgType string
+	typ     websocket.MessageType
+	data    []byte
+}
+
+// transport carries a client's frames to the other end.
+type transport interface {
+	write(ctx context.Context, f frame) error
+	close(code websocket.StatusCode, reason string)
+}
+
+type wsTransport struct {
+	conn *websocket.Conn
+}
+
+func (t wsTransport) write(ctx context.Context, f frame) error {
+	return t.conn.Write(ctx, f.typ, f.data)
+}
+
+func (t wsTransport) close(code websocket.StatusCode, reason string) {
+	t.conn.Close(code, reason)
 }
 
 func NewClient(playerID, gameCode string, conn *websocket.Conn) *Client {
-	return &Client{
+	return newClient(playerID, gameCode, wsTransport{conn})
+}
+
+func newClient(playerID, gameCode string, out transport) *Client {
+	c := &Client{
 		PlayerID: playerID,
 		GameCode: gameCode,
-		conn:     conn,
+		Version:  game.ProtocolVersion,
+		Codec:    CodecJSON,
+		out:      out,
+		limiter:  newInboundLimiter(),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
 	}
+	go c.writeLoop()
+	return c
 }
 
+// Send queues a message for delivery without waiting for the write. The
+// message is encoded straight away, while the caller still holds whatever
+// lock guards its data. A countdown tick replaces any older tick of the
+// same type still queued. If the queue is full the client is evicted.
+// Messages the client's protocol version doesn't know are dropped.
 func (c *Client) Send(msg game.OutgoingMessage) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	data, err := EncodeMessage(msg)
+	msg, ok := game.ForVersion(msg, c.Version)
+	if !ok {
+		return nil
+	}
+	data, err := EncodeMessage(c.Codec, msg)
 	if err != nil {
 		return err
 	}
-	return c.conn.Write(context.Background(), websocket.MessageText, data)
+	return c.sendEncoded(msg.Type, data)
 }
 
-func (c *Client) ReadMessage(ctx context.Context) (game.IncomingMessage, error) {
-	_, data, err := c.conn.Read(ctx)
-	if err != nil {
-		return game.IncomingMessage{}, err
+// sendEncoded queues a message already encoded with c.Codec.
+func (c *Client) sendEncoded(msgType string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return errClientClosed
+	default:
 	}
-	return DecodeMessage(data)
-}
-
-func (c *Client) Close() {
-	c.conn.Close(websocket.StatusNormalClosure, "")
-}
 
-// ClientRegistry tracks all connected clients.
-type ClientRegistry struct {
-	mu      sync.RWMutex
-	clients map[string]*Client // playerID -> client
-}
-
-func NewClientRegistry() *ClientRegistry {
-	return &ClientRegistry{
-		clients: make(map[string]*Client),
+	if game.Supersedable(msgType) {
+		for i, q := range c.queue {
+			if q.msgType == msgType {
+				c.queue = append(c.queue[:i], c.queue[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(c.queue) >= maxQueue {
+		log.Printf("evicting %s: %d messages behind", c.PlayerID, len(c.queue))
+		c.evict(websocket.StatusTryAgainLater, "too far behind")
+		return errSlowConsumer
+	}
+	c.queue = append(c.queue, frame{msgType: msgType, typ: c.Codec.frameType(), data: data})
+	select {
+	case c.wake <- struct{}{}:
+	default: // writer already signalled
 	}
+	return nil
 }
 
-func (cr *ClientRegistry) Add(c *Client) {
-	cr.mu.Lock()
-	defer cr.mu.Unlock()
-	cr.clients[c.PlayerID] = c
-}
+// writeLoop writes queued messages in order until the client is closed or
+// a write fails.
+func (c *Client) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.wake:
+		}
+		for {
+			c.mu.Lock()
+			if len(c.queue) == 0 {
+				c.mu.Unlock()
+				break
+			}
+			f := c.queue[0]
+			c.queue[0] = frame{}
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
 
-func (cr *ClientRegistry) Remove(playerID string) {
-	cr.mu.Lock()
-	defer cr.mu.Unlock()
-	delete(cr.clients, playerID)
+			ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+			err := c.out.write(ctx, f)
+			cancel()
+			if err != nil {
+				log.Printf("write to %s: %v", c.PlayerID, err)
+				c.mu.Lock()
+				c.evict(websocket.StatusGoingAway, "write failed")
+				c.mu.Unlock()
+				return
+			}
+		}
+	}
 }
 
-func (cr *ClientRegistry) Get(playerID string) *Client {
-	cr.mu.RLock()
-	defer cr.mu.RUnlock()
-	return cr.clients[playerID]
+// kick closes the connection with the given status, e.g. because a newer
+// one replaced it.
+func (c *Client) kick(code websocket.StatusCode, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict(code, reason)
 }
 
-func (cr *ClientRegistry) SendTo(playerID string, msg game.OutgoingMessage) {
-	c := cr.Get(playerID)
-	if c != nil {
-		c.Send(msg)
-	}
+// evict drops the queue and closes the connection; the read loop then
+// sees the socket close and runs the usual disconnect. Must be called with
+// c.mu held.
+func (c *Client) evict(code websocket.StatusCode, reason string) {
+	c.queue = nil
+	c.closeOnce.Do(func() { close(c.done) })
+	go c.out.close(code, reason) // the close handshake can block on a stalled peer
 }
 
-func (cr *ClientRegistry) BroadcastToGame(gameCode string, msg game.OutgoingMessage) {
-	cr.mu.RLock()
-	defer cr.mu.RUnlock()
-	for _, c := range cr.clients {
-		if c.GameCode == gameCode {
-			go c.Send(msg)
-		}
-	}
+func (c *Client) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+	c.out.close(websocket.StatusNormalClosure, "")
 }
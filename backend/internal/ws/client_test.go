@@ -0,0 +1,134 @@
+package ws
+
+import (
+	"context"
+	"drawl/internal/game"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// dialClient returns a server-side Client and the connection reading from it.
+func dialClient(t *testing.T) (*Client, *websocket.Conn) {
+	t.Helper()
+	clients := make(chan *Client, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		c := NewClient("p1", "GAME1", conn)
+		clients <- c
+		<-c.done
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn.SetReadLimit(1 << 20)
+	c := <-clients
+	t.Cleanup(func() {
+		conn.CloseNow()
+		c.Close()
+	})
+	return c, conn
+}
+
+func TestClient_DeliversInOrder(t *testing.T) {
+	c, conn := dialClient(t)
+	registry := NewClientRegistry()
+	registry.Add(c)
+
+	const n = 200
+	for i := 1; i <= n; i++ {
+		registry.BroadcastToGame("GAME1", game.OutgoingMessage{Type: game.MsgPlayerJoined, Seq: uint64(i)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 1; i <= n; i++ {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+		var msg struct{ Seq uint64 }
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if msg.Seq != uint64(i) {
+			t.Fatalf("message %d has seq %d", i, msg.Seq)
+		}
+	}
+}
+
+func TestClient_SendAfterClose(t *testing.T) {
+	c, conn := dialClient(t)
+	conn.CloseNow()
+	c.Close()
+	if err := c.Send(game.OutgoingMessage{Type: game.MsgTurnTick}); err != errClientClosed {
+		t.Errorf("Send after Close = %v, want errClientClosed", err)
+	}
+}
+
+// stall fills the socket with large messages the peer never reads, until
+// the writer is stuck with messages still queued.
+func stall(t *testing.T, c *Client, n int) {
+	t.Helper()
+	big := strings.Repeat("x", 256<<10)
+	for i := 0; i < n; i++ {
+		if err := c.Send(game.OutgoingMessage{Type: game.MsgGameState, Data: big}); err != nil {
+			t.Fatalf("Send %d: %v", i, err)
+		}
+	}
+}
+
+func TestClient_CoalescesTicks(t *testing.T) {
+	c, _ := dialClient(t)
+	stall(t, c, 64)
+
+	for i := 1; i <= 10; i++ {
+		c.Send(game.OutgoingMessage{Type: game.MsgTurnTick, Data: game.TickData{Remaining: 60 - i}})
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ticks []string
+	for _, f := range c.queue {
+		if f.msgType == game.MsgTurnTick {
+			ticks = append(ticks, string(f.data))
+		}
+	}
+	if len(ticks) != 1 || !strings.Contains(ticks[0], `"remaining":50`) {
+		t.Errorf("queued ticks = %v, want only the latest", ticks)
+	}
+}
+
+func TestClient_EvictsSlowConsumer(t *testing.T) {
+	c, _ := dialClient(t)
+	stall(t, c, 64)
+
+	var err error
+	for i := 0; i <= maxQueue && err == nil; i++ {
+		err = c.Send(game.OutgoingMessage{Type: game.MsgPlayerJoined})
+	}
+	if err != errSlowConsumer {
+		t.Fatalf("Send on a full queue = %v, want errSlowConsumer", err)
+	}
+	select {
+	case <-c.done:
+	default:
+		t.Error("evicted client should be closed")
+	}
+	if err := c.Send(game.OutgoingMessage{Type: game.MsgPlayerJoined}); err != errClientClosed {
+		t.Errorf("Send after eviction = %v, want errClientClosed", err)
+	}
+}
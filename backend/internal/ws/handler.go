@@ -11,23 +11,31 @@ import (
 )
 
 type Handler struct {
-	Hub      *hub.Hub
-	Registry *ClientRegistry
+	Hub         *hub.Hub
+	Registry    *ClientRegistry
+	Heartbeat   Heartbeat
+	Compression websocket.CompressionMode // permessage-deflate, if the client supports it
 }
 
 func NewHandler(h *hub.Hub, registry *ClientRegistry) *Handler {
-	return &Handler{Hub: h, Registry: registry}
+	return &Handler{
+		Hub:         h,
+		Registry:    registry,
+		Heartbeat:   DefaultHeartbeat,
+		Compression: websocket.CompressionNoContextTakeover,
+	}
 }
 
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		InsecureSkipVerify: true, // Allow all origins in dev
+		CompressionMode:    h.Compression,
 	})
 	if err != nil {
 		log.Printf("websocket accept error: %v", err)
 		return
 	}
-	conn.SetReadLimit(10 * 1024 * 1024) // 10MB for drawing data
+	conn.SetReadLimit(maxFrameBytes)
 
 	token := r.URL.Query().Get("token")
 	gameCode := r.URL.Query().Get("game")
@@ -37,6 +45,17 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := game.NegotiateVersion(r.URL.Query().Get("v"))
+	if err != nil {
+		conn.Close(websocket.StatusPolicyViolation, err.Error())
+		return
+	}
+	codec, err := ParseCodec(r.URL.Query().Get("codec"))
+	if err != nil {
+		conn.Close(websocket.StatusPolicyViolation, err.Error())
+		return
+	}
+
 	g := h.Hub.GetGame(gameCode)
 	if g == nil {
 		conn.Close(websocket.StatusPolicyViolation, "game not found")
@@ -44,40 +63,26 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Find player by token
-	player := g.State.FindPlayerByToken(token)
+	player := g.PlayerByToken(token)
 	if player == nil {
 		conn.Close(websocket.StatusPolicyViolation, "invalid token")
 		return
 	}
 
 	client := NewClient(player.ID, gameCode, conn)
+	client.Version = version
+	client.Codec = codec
+	client.act = newActivity(g, player.ID)
 	h.Registry.Add(client)
-	defer func() {
-		h.Registry.Remove(player.ID)
-		g.HandleDisconnect(player.ID)
-		client.Close()
-		if g.IsEmpty() {
-			h.Hub.RemoveGame(gameCode)
-		}
-	}()
+	g.HandleReconnect(player.ID) // sends the initial game state
+	defer h.leave(g, client)
 
-	// Send initial game state
-	client.Send(game.OutgoingMessage{
-		Type: game.MsgGameState,
-		Data: map[string]interface{}{
-			"code":        g.State.Code,
-			"phase":       g.State.Phase,
-			"players":     g.State.Players,
-			"round":       g.State.Round,
-			"totalRounds": g.State.TotalRounds,
-			"hostId":      g.State.HostID,
-			"playerId":    player.ID,
-		},
-	})
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go h.Heartbeat.run(ctx, conn, client.act)
 
-	ctx := r.Context()
 	for {
-		msg, err := client.ReadMessage(ctx)
+		typ, data, err := conn.Read(ctx)
 		if err != nil {
 			if ctx.Err() != nil || websocket.CloseStatus(err) != -1 {
 				return
@@ -88,6 +93,24 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			log.Printf("read error from %s: %v", player.ID, err)
 			return
 		}
-		g.HandleMessage(player.ID, msg)
+		if !client.receive(g, codecFor(typ), data) {
+			log.Printf("closing %s: too many rejected messages", player.ID)
+			conn.Close(websocket.StatusPolicyViolation, "too many rejected messages")
+			return
+		}
+	}
+}
+
+// leave unregisters a connection. The player only leaves the game when
+// their last connection does.
+func (h *Handler) leave(g *game.Game, c *Client) {
+	last := h.Registry.Remove(c)
+	c.Close()
+	if !last {
+		return
+	}
+	g.HandleDisconnect(c.PlayerID)
+	if g.IsEmpty() {
+		h.Hub.RemoveGame(c.GameCode)
 	}
 }
@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"context"
+	"drawl/internal/game"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// Heartbeat configures how connections are checked for liveness.
+type Heartbeat struct {
+	Interval  time.Duration // between pings
+	Timeout   time.Duration // to wait for each pong before dropping the connection
+	IdleAfter time.Duration // without a message from the player before they show as idle
+}
+
+var DefaultHeartbeat = Heartbeat{
+	Interval:  20 * time.Second,
+	Timeout:   10 * time.Second,
+	IdleAfter: 2 * time.Minute,
+}
+
+// activity tracks when a connected player last sent anything, moving them
+// between online and idle.
+type activity struct {
+	game     *game.Game
+	playerID string
+	lastSeen atomic.Int64 // unix nanoseconds
+	idle     atomic.Bool
+}
+
+func newActivity(g *game.Game, playerID string) *activity {
+	a := &activity{game: g, playerID: playerID}
+	a.lastSeen.Store(time.Now().UnixNano())
+	return a
+}
+
+// seen records a message from the player.
+func (a *activity) seen() {
+	a.lastSeen.Store(time.Now().UnixNano())
+	if a.idle.CompareAndSwap(true, false) {
+		a.game.SetPresence(a.playerID, game.PresenceOnline)
+	}
+}
+
+// checkIdle marks the player idle if they've been quiet for too long.
+func (a *activity) checkIdle(after time.Duration) {
+	quiet := time.Since(time.Unix(0, a.lastSeen.Load()))
+	if quiet >= after && a.idle.CompareAndSwap(false, true) {
+		a.game.SetPresence(a.playerID, game.PresenceIdle)
+	}
+}
+
+// run pings conn until ctx ends, closing it if a pong doesn't come back in
+// time; the read loop then sees the socket close and the player is
+// disconnected. Pongs are handled by the read loop, so one must be running.
+func (hb Heartbeat) run(ctx context.Context, conn *websocket.Conn, a *activity) {
+	t := time.NewTicker(hb.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, hb.Timeout)
+		err := conn.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("no pong from %s: %v", a.playerID, err)
+				conn.CloseNow()
+			}
+			return
+		}
+		a.checkIdle(hb.IdleAfter)
+	}
+}
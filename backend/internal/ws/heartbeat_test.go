@@ -0,0 +1,100 @@
+package ws
+
+import (
+	"context"
+	"drawl/internal/game"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// heartbeatServer runs hb on every accepted connection alongside a read
+// loop, and reports when the server side sees the connection end.
+func heartbeatServer(t *testing.T, hb Heartbeat, a *activity) (*websocket.Conn, <-chan struct{}) {
+	t.Helper()
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go hb.run(ctx, conn, a)
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				close(closed)
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn, closed
+}
+
+// newPresenceGame returns a game with one connected player, and the
+// presence changes it broadcasts from then on.
+func newPresenceGame() (*game.Game, *game.Player, <-chan game.Presence) {
+	changes := make(chan game.Presence, 16)
+	bcast := func(msg game.OutgoingMessage) {
+		if msg.Type == game.MsgPresence {
+			changes <- msg.Data.(game.PresenceData).Presence
+		}
+	}
+	p := game.NewHumanPlayer("P0")
+	g := game.NewGame("TEST1", p, func(string, game.OutgoingMessage) {}, bcast, nil)
+	g.HandleReconnect(p.ID)
+	<-changes // online
+	return g, p, changes
+}
+
+func TestHeartbeat_DropsUnresponsiveConnection(t *testing.T) {
+	g, p, _ := newPresenceGame()
+	hb := Heartbeat{Interval: 20 * time.Millisecond, Timeout: 50 * time.Millisecond, IdleAfter: time.Minute}
+	_, closed := heartbeatServer(t, hb, newActivity(g, p.ID))
+
+	// The client never reads, so it never answers a ping.
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection without pongs should be closed")
+	}
+}
+
+func TestHeartbeat_IdleAndBack(t *testing.T) {
+	g, p, changes := newPresenceGame()
+	a := newActivity(g, p.ID)
+	hb := Heartbeat{Interval: 10 * time.Millisecond, Timeout: time.Second, IdleAfter: 30 * time.Millisecond}
+	conn, closed := heartbeatServer(t, hb, a)
+	conn.CloseRead(context.Background()) // answer pings
+
+	select {
+	case got := <-changes:
+		if got != game.PresenceIdle {
+			t.Fatalf("presence = %s, want idle", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("quiet player should become idle")
+	}
+	select {
+	case <-closed:
+		t.Fatal("a connection answering pings should stay open")
+	default:
+	}
+
+	a.seen()
+	if got := <-changes; got != game.PresenceOnline {
+		t.Errorf("presence after a message = %s, want online", got)
+	}
+}
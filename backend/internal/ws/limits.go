@@ -0,0 +1,149 @@
+package ws
+
+import (
+	"drawl/internal/game"
+	"fmt"
+	"time"
+)
+
+// Inbound limits. Each connection gets a token bucket per message type and
+// a size cap on each type's frames. Only the type is read before they're
+// checked, so an oversized or unwanted message is never decoded. A
+// connection that keeps sending rejected messages is closed.
+
+// messageLimit bounds one message type on one connection.
+type messageLimit struct {
+	rate     float64 // sustained messages per second
+	burst    float64 // messages allowed at once
+	maxBytes int     // largest accepted frame, envelope included
+}
+
+var messageLimits = map[string]messageLimit{
+	game.MsgSubmitDrawing:  {rate: 1, burst: 3, maxBytes: game.MaxDrawingBytes + 1<<10},
+	game.MsgSubmitGuess:    {rate: 1, burst: 3, maxBytes: 1 << 10},
+	game.MsgSubmitVotes:    {rate: 1, burst: 3, maxBytes: 8 << 10},
+	game.MsgAddAI:          {rate: 0.5, burst: 4, maxBytes: 256},
+	game.MsgStartGame:      {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgKickPlayer:     {rate: 1, burst: 4, maxBytes: 256},
+	game.MsgEndVoting:      {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgPlayAgain:      {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgUpdateSettings: {rate: 2, burst: 10, maxBytes: 8 << 10},
+	game.MsgAssignTeam:     {rate: 4, burst: 16, maxBytes: 256},
+	game.MsgNudge:          {rate: 0.5, burst: 3, maxBytes: 256},
+	game.MsgPauseGame:      {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgResumeGame:     {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgAbortGame:      {rate: 0.5, burst: 2, maxBytes: 256},
+	game.MsgResync:         {rate: 0.5, burst: 3, maxBytes: 256},
+}
+
+// unknownLimit covers every type not listed above, sharing one bucket.
+var unknownLimit = messageLimit{rate: 1, burst: 5, maxBytes: 256}
+
+// maxFrameBytes is the read limit for a whole frame: the largest payload
+// plus room for the envelope.
+const maxFrameBytes = game.MaxDrawingBytes + 2<<10
+
+// maxStrikes is how many rejected messages in a row close the connection.
+const maxStrikes = 20
+
+// tokenBucket refills at rate tokens per second up to burst.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(lim messageLimit, now time.Time) bool {
+	b.tokens = min(lim.burst, b.tokens+now.Sub(b.last).Seconds()*lim.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// inboundLimiter applies messageLimits to one connection. Client.receive
+// serializes access to it.
+type inboundLimiter struct {
+	buckets map[string]*tokenBucket
+	strikes int // rejected messages since the last accepted one
+}
+
+func newInboundLimiter() *inboundLimiter {
+	return &inboundLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// check returns why a frame of the given type and size should be
+// rejected, or nil to accept it.
+func (l *inboundLimiter) check(msgType string, size int, now time.Time) *game.ErrorData {
+	key := msgType
+	lim, ok := messageLimits[key]
+	if !ok {
+		key, lim = "", unknownLimit
+	}
+	b := l.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: lim.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	var e *game.ErrorData
+	switch {
+	case size > lim.maxBytes:
+		e = &game.ErrorData{
+			Message: fmt.Sprintf("message too large (%d bytes, limit %d)", size, lim.maxBytes),
+			Code:    game.ErrCodeTooLarge,
+		}
+	case !b.take(lim, now):
+		e = &game.ErrorData{Message: "slow down", Code: game.ErrCodeRateLimited}
+	}
+	if e == nil {
+		l.strikes = 0
+		return nil
+	}
+	l.strikes++
+	e.Type = msgType
+	return e
+}
+
+// strike counts a message rejected for some other reason, e.g. because
+// it couldn't be decoded.
+func (l *inboundLimiter) strike() {
+	l.strikes++
+}
+
+// exhausted reports whether the connection has sent too many rejected
+// messages in a row.
+func (l *inboundLimiter) exhausted() bool {
+	return l.strikes >= maxStrikes
+}
+
+// receive checks a frame from c against c's limits, then decodes it and
+// passes it to the game, or rejects it. It reports false once the
+// connection has sent too many rejected messages in a row and should be
+// closed.
+func (c *Client) receive(g *game.Game, codec Codec, data []byte) bool {
+	c.inMu.Lock()
+	defer c.inMu.Unlock()
+	invalid := game.ErrorData{Message: "invalid message", Code: game.ErrCodeInvalid}
+	msgType, err := peekType(codec, data)
+	if err != nil {
+		c.limiter.strike()
+		g.Reject(c.PlayerID, invalid)
+		return !c.limiter.exhausted()
+	}
+	if e := c.limiter.check(msgType, len(data), time.Now()); e != nil {
+		g.Reject(c.PlayerID, *e)
+		return !c.limiter.exhausted()
+	}
+	msg, err := DecodeMessage(codec, data)
+	if err != nil {
+		c.limiter.strike()
+		invalid.Type = msgType
+		g.Reject(c.PlayerID, invalid)
+		return !c.limiter.exhausted()
+	}
+	c.act.seen()
+	g.HandleMessage(c.PlayerID, msg)
+	return true
+}
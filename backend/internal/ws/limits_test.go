@@ -0,0 +1,177 @@
+package ws
+
+import (
+	"drawl/internal/game"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInboundLimiter_RateLimitsPerType(t *testing.T) {
+	l := newInboundLimiter()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if e := l.check(game.MsgSubmitGuess, 50, now); e != nil {
+			t.Fatalf("guess %d rejected within burst: %+v", i, e)
+		}
+	}
+	e := l.check(game.MsgSubmitGuess, 50, now)
+	if e == nil || e.Code != game.ErrCodeRateLimited || e.Type != game.MsgSubmitGuess {
+		t.Fatalf("guess past burst: got %+v, want rate_limited for %s", e, game.MsgSubmitGuess)
+	}
+
+	// Other types have their own bucket.
+	if e := l.check(game.MsgNudge, 30, now); e != nil {
+		t.Errorf("nudge rejected after guesses ran out: %+v", e)
+	}
+
+	// The bucket refills at its rate.
+	if e := l.check(game.MsgSubmitGuess, 50, now.Add(time.Second)); e != nil {
+		t.Errorf("guess rejected after refilling: %+v", e)
+	}
+}
+
+func TestInboundLimiter_SizeCapPerType(t *testing.T) {
+	l := newInboundLimiter()
+	now := time.Now()
+
+	e := l.check(game.MsgSubmitGuess, 2000, now)
+	if e == nil || e.Code != game.ErrCodeTooLarge {
+		t.Fatalf("oversized guess: got %+v, want too_large", e)
+	}
+	if e := l.check(game.MsgSubmitDrawing, 2000, now); e != nil {
+		t.Errorf("drawing of the same size rejected: %+v", e)
+	}
+}
+
+// The game's own drawing cap is the one that applies.
+func TestInboundLimiter_AdmitsLargestDrawing(t *testing.T) {
+	l := newInboundLimiter()
+	size := len(`{"type":"submit_drawing","data":{"drawing":""}}`) + game.MaxDrawingBytes
+	if e := l.check(game.MsgSubmitDrawing, size, time.Now()); e != nil {
+		t.Errorf("drawing at the game's cap rejected: %+v", e)
+	}
+	if size > maxFrameBytes {
+		t.Errorf("read limit %d is below the largest drawing frame %d", maxFrameBytes, size)
+	}
+}
+
+func TestInboundLimiter_UnknownTypesShareBucket(t *testing.T) {
+	l := newInboundLimiter()
+	now := time.Now()
+	for i := 0; i < int(unknownLimit.burst); i++ {
+		if e := l.check("made_up_"+string(rune('a'+i)), 30, now); e != nil {
+			t.Fatalf("unknown message %d rejected within burst: %+v", i, e)
+		}
+	}
+	if e := l.check("another", 30, now); e == nil || e.Code != game.ErrCodeRateLimited {
+		t.Errorf("new unknown type past the shared burst: got %+v, want rate_limited", e)
+	}
+}
+
+func TestInboundLimiter_Strikes(t *testing.T) {
+	l := newInboundLimiter()
+	now := time.Now()
+
+	for i := 0; i < maxStrikes-1; i++ {
+		l.check(game.MsgNudge, 300, now)
+	}
+	if l.exhausted() {
+		t.Fatalf("exhausted after %d strikes", maxStrikes-1)
+	}
+	l.check(game.MsgNudge, 30, now)
+	if l.strikes != 0 {
+		t.Fatalf("accepted message left %d strikes, want 0", l.strikes)
+	}
+
+	for i := 0; i < maxStrikes-1; i++ {
+		l.check(game.MsgNudge, 300, now)
+	}
+	l.strike()
+	if !l.exhausted() {
+		t.Errorf("not exhausted after %d strikes in a row", maxStrikes)
+	}
+}
+
+func TestPeekType(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{`{"type":"add_ai","data":{}}`, "add_ai", false},
+		{`{"data":{"guess":"cat"},"type":"submit_guess"}`, "submit_guess", false},
+		{`{"type":"add_ai","data":{"unterminated`, "add_ai", false}, // the rest isn't read
+		{`{"data":{}}`, "", true},
+		{`["add_ai"]`, "", true},
+		{`{"type":7}`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := peekType(CodecJSON, []byte(tt.data))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("peekType(%s) = %q, %v; want %q, err %v", tt.data, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	data, err := EncodeMessage(CodecCBOR, game.OutgoingMessage{Type: game.MsgAddAI, Data: map[string]string{"x": "y"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := peekType(CodecCBOR, data); err != nil || got != game.MsgAddAI {
+		t.Errorf("peekType(cbor) = %q, %v; want %q", got, err, game.MsgAddAI)
+	}
+}
+
+// receiveGame returns a game whose host is connected as c, recording the
+// errors the game sends them.
+func receiveGame(t *testing.T) (*Client, *game.Game, func() []game.ErrorData) {
+	t.Helper()
+	var mu sync.Mutex
+	var errs []game.ErrorData
+	host := game.NewHumanPlayer("Alice")
+	g := game.NewGame("GAME1", host, func(_ string, msg game.OutgoingMessage) {
+		if msg.Type == game.MsgError {
+			mu.Lock()
+			errs = append(errs, msg.Data.(game.ErrorData))
+			mu.Unlock()
+		}
+	}, func(game.OutgoingMessage) {}, nil)
+	c := benchClient(host.ID, "GAME1")
+	c.limiter = newInboundLimiter()
+	c.act = newActivity(g, host.ID)
+	return c, g, func() []game.ErrorData {
+		mu.Lock()
+		defer mu.Unlock()
+		return errs
+	}
+}
+
+func TestReceive_RejectsOversizedBeforeDecoding(t *testing.T) {
+	c, g, errs := receiveGame(t)
+
+	// Far too big for add_ai, and not even valid JSON past the type.
+	data := []byte(`{"type":"add_ai","data":"` + strings.Repeat("x", 1<<20))
+	c.receive(g, CodecJSON, data)
+	if e := errs(); len(e) != 1 || e[0].Code != game.ErrCodeTooLarge || e[0].Type != game.MsgAddAI {
+		t.Fatalf("errors = %+v, want one too_large for add_ai", e)
+	}
+
+	c.receive(g, CodecJSON, []byte(`{"type":"add_ai","data":`))
+	if e := errs(); len(e) != 2 || e[1].Code != game.ErrCodeInvalid {
+		t.Fatalf("errors = %+v, want invalid_message for a truncated frame", e)
+	}
+}
+
+func TestReceive_ClosesAfterTooManyRejections(t *testing.T) {
+	c, g, _ := receiveGame(t)
+	for i := 1; i < maxStrikes; i++ {
+		if !c.receive(g, CodecJSON, []byte(`nonsense`)) {
+			t.Fatalf("receive gave up after %d rejections", i)
+		}
+	}
+	if c.receive(g, CodecJSON, []byte(`nonsense`)) {
+		t.Errorf("receive still accepting after %d rejections", maxStrikes)
+	}
+}
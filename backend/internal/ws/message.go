@@ -1,16 +1,211 @@
 package ws
 
 import (
+	"bytes"
 	"drawl/internal/game"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"nhooyr.io/websocket"
+)
+
+// Codec is a wire encoding for messages, chosen by the client with the
+// codec query parameter when connecting.
+type Codec string
+
+const (
+	// CodecJSON sends JSON in text frames. The default.
+	CodecJSON Codec = "json"
+	// CodecCBOR sends CBOR in binary frames. Drawings (drawing fields and
+	// the prompt of a guess turn) travel as CBOR byte strings holding the
+	// raw PNG instead of base64 data URLs, which saves the third that
+	// base64 adds.
+	CodecCBOR Codec = "cbor"
 )
 
-func EncodeMessage(msg game.OutgoingMessage) ([]byte, error) {
-	return json.Marshal(msg)
+// ParseCodec returns the codec a client asked for; empty means JSON.
+func ParseCodec(s string) (Codec, error) {
+	switch Codec(s) {
+	case "", CodecJSON:
+		return CodecJSON, nil
+	case CodecCBOR:
+		return CodecCBOR, nil
+	}
+	return "", fmt.Errorf("unsupported codec %q", s)
+}
+
+// frameType is the WebSocket frame type messages are sent in.
+func (c Codec) frameType() websocket.MessageType {
+	if c == CodecCBOR {
+		return websocket.MessageBinary
+	}
+	return websocket.MessageText
 }
 
-func DecodeMessage(data []byte) (game.IncomingMessage, error) {
+// codecFor returns the codec for a frame a client sent. Clients may always
+// fall back to JSON text frames.
+func codecFor(typ websocket.MessageType) Codec {
+	if typ == websocket.MessageBinary {
+		return CodecCBOR
+	}
+	return CodecJSON
+}
+
+func EncodeMessage(codec Codec, msg game.OutgoingMessage) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil || codec != CodecCBOR {
+		return data, err
+	}
+	// Go through JSON so CBOR carries exactly the fields and names the
+	// schema describes.
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return cborEnc.Marshal(toCBOR(v))
+}
+
+func DecodeMessage(codec Codec, data []byte) (game.IncomingMessage, error) {
 	var msg game.IncomingMessage
-	err := json.Unmarshal(data, &msg)
-	return msg, err
+	if codec != CodecCBOR {
+		err := json.Unmarshal(data, &msg)
+		return msg, err
+	}
+	var v struct {
+		Type string      `cbor:"type"`
+		Data interface{} `cbor:"data"`
+	}
+	if err := cborDec.Unmarshal(data, &v); err != nil {
+		return msg, err
+	}
+	raw, err := json.Marshal(fromCBOR(v.Data))
+	if err != nil {
+		return msg, err
+	}
+	msg.Type = v.Type
+	msg.Data = raw
+	return msg, nil
+}
+
+// peekType reads a message's type without decoding the rest of it. JSON
+// values before "type" are scanned but not decoded; CBOR ones are skipped
+// by length.
+func peekType(codec Codec, data []byte) (string, error) {
+	if codec == CodecCBOR {
+		var v struct {
+			Type string `cbor:"type"`
+		}
+		if err := cborDec.Unmarshal(data, &v); err != nil {
+			return "", err
+		}
+		return v.Type, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return "", errors.New("message is not an object")
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		if key == "type" {
+			var t string
+			err := dec.Decode(&t)
+			return t, err
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("message has no type")
+}
+
+var (
+	cborEnc, _ = cbor.EncOptions{}.EncMode()
+	cborDec, _ = cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+)
+
+const pngDataURL = "data:image/png;base64,"
+
+// guessTurn is how a guess turn's turnType reads in decoded JSON.
+var guessTurn = json.Number(strconv.Itoa(int(game.TurnGuess)))
+
+// drawingField reports whether a key in an object holds a drawing: a
+// drawing field, or the prompt of a guess turn. Text that happens to look
+// like a data URL anywhere else stays text.
+func drawingField(obj map[string]interface{}, key string) bool {
+	switch key {
+	case "drawing":
+		return true
+	case "prompt":
+		switch t := obj["turnType"].(type) {
+		case json.Number: // from JSON
+			return t == guessTurn
+		case uint64: // from CBOR
+			return t == uint64(game.TurnGuess)
+		}
+	}
+	return false
+}
+
+// toCBOR turns a decoded JSON value into one for CBOR: numbers become
+// integers where they can, and drawings become raw PNG bytes.
+func toCBOR(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		drawings := make(map[string]bool)
+		for k := range v {
+			drawings[k] = drawingField(v, k)
+		}
+		for k, e := range v {
+			if s, ok := e.(string); ok && drawings[k] && strings.HasPrefix(s, pngDataURL) {
+				if png, err := base64.StdEncoding.DecodeString(s[len(pngDataURL):]); err == nil {
+					v[k] = png
+					continue
+				}
+			}
+			v[k] = toCBOR(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = toCBOR(e)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// fromCBOR reverses toCBOR on a decoded CBOR value so it can be passed on
+// as JSON.
+func fromCBOR(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if png, ok := e.([]byte); ok && drawingField(v, k) {
+				v[k] = pngDataURL + base64.StdEncoding.EncodeToString(png)
+				continue
+			}
+			v[k] = fromCBOR(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = fromCBOR(e)
+		}
+	}
+	return v
 }
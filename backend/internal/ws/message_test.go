@@ -0,0 +1,214 @@
+package ws
+
+import (
+	"bytes"
+	"compress/flate"
+	"drawl/internal/game"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// testDrawing renders a canvas-sized PNG with a few random strokes, about
+// what a player manages in a turn, as a data URL.
+func testDrawing(rng *rand.Rand) string {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for s := 0; s < 12; s++ {
+		x, y := rng.Intn(400), rng.Intn(300)
+		c := color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 0xff}
+		for step := 0; step < 200; step++ {
+			x = min(max(x+rng.Intn(7)-3, 2), 397)
+			y = min(max(y+rng.Intn(7)-3, 2), 297)
+			for dx := -2; dx <= 2; dx++ {
+				for dy := -2; dy <= 2; dy++ {
+					img.SetRGBA(x+dx, y+dy, c)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// revealMessage is the game_over sent to an 8-player table: eight chains
+// of eight entries, alternating drawings and guesses.
+func revealMessage() game.OutgoingMessage {
+	rng := rand.New(rand.NewSource(1))
+	var chains []*game.Chain
+	for c := 0; c < 8; c++ {
+		chain := &game.Chain{OriginalWord: "giraffe", OwnerID: fmt.Sprintf("player%d", c)}
+		for e := 0; e < 8; e++ {
+			entry := game.ChainEntry{ID: fmt.Sprintf("%08x", rng.Uint32()), PlayerID: fmt.Sprintf("player%d", (c+e)%8)}
+			if e%2 == 0 {
+				entry.Drawing = testDrawing(rng)
+			} else {
+				entry.Type = game.TurnGuess
+				entry.Guess = "a tall horse eating a tree"
+			}
+			chain.Entries = append(chain.Entries, entry)
+		}
+		chains = append(chains, chain)
+	}
+	return game.OutgoingMessage{Type: game.MsgGameOver, Seq: 42, Data: game.GameOverData{
+		Chains:          chains,
+		Scores:          map[string]int{"player0": 3, "player1": 1},
+		VoteTime:        90,
+		AwardCategories: game.AwardCategories,
+	}}
+}
+
+func TestCBOR_DrawingsTravelAsBytes(t *testing.T) {
+	drawing := testDrawing(rand.New(rand.NewSource(1)))
+	msg := game.OutgoingMessage{Type: game.MsgTurnStart, Seq: 7, Data: game.TurnStartData{
+		Round: 1, TotalRounds: 4, TurnType: game.TurnGuess, Prompt: drawing, TimeLimit: 60,
+	}}
+	data, err := EncodeMessage(CodecCBOR, msg)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	var got struct {
+		Type string
+		Seq  uint64
+		Data struct {
+			Prompt    []byte
+			TimeLimit int
+		}
+	}
+	dec, _ := cbor.DecOptions{}.DecMode()
+	if err := dec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	png, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(drawing, pngDataURL))
+	if got.Type != game.MsgTurnStart || got.Seq != 7 || got.Data.TimeLimit != 60 {
+		t.Errorf("decoded %+v", got)
+	}
+	if !bytes.Equal(got.Data.Prompt, png) {
+		t.Error("prompt drawing should be the raw PNG bytes")
+	}
+}
+
+func TestCBOR_DecodeIncoming(t *testing.T) {
+	drawing := testDrawing(rand.New(rand.NewSource(2)))
+	png, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(drawing, pngDataURL))
+	data, _ := cbor.Marshal(map[string]interface{}{
+		"type": game.MsgSubmitDrawing,
+		"data": map[string]interface{}{"drawing": png, "chainIdx": 2},
+	})
+
+	msg, err := DecodeMessage(CodecCBOR, data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var d struct {
+		Drawing  string `json:"drawing"`
+		ChainIdx *int   `json:"chainIdx"`
+	}
+	if err := json.Unmarshal(msg.Data, &d); err != nil {
+		t.Fatalf("data is not JSON: %v", err)
+	}
+	if msg.Type != game.MsgSubmitDrawing || d.Drawing != drawing || d.ChainIdx == nil || *d.ChainIdx != 2 {
+		t.Errorf("decoded %s %+v", msg.Type, d)
+	}
+}
+
+func TestParseCodec(t *testing.T) {
+	for in, want := range map[string]Codec{"": CodecJSON, "json": CodecJSON, "cbor": CodecCBOR} {
+		if got, err := ParseCodec(in); err != nil || got != want {
+			t.Errorf("ParseCodec(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseCodec("msgpack"); err == nil {
+		t.Error("expected an error for an unknown codec")
+	}
+}
+
+func deflated(data []byte) int {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	w.Write(data)
+	w.Close()
+	return buf.Len()
+}
+
+// BenchmarkRevealSize reports the size of an 8-player reveal in each
+// encoding, with and without permessage-deflate.
+func BenchmarkRevealSize(b *testing.B) {
+	msg := revealMessage()
+	for _, codec := range []Codec{CodecJSON, CodecCBOR} {
+		b.Run(string(codec), func(b *testing.B) {
+			var data []byte
+			for i := 0; i < b.N; i++ {
+				data, _ = EncodeMessage(codec, msg)
+			}
+			b.ReportMetric(float64(len(data)), "bytes")
+			b.ReportMetric(float64(deflated(data)), "deflated-bytes")
+		})
+	}
+}
+
+// Only drawings become bytes; text that looks like a data URL stays text.
+func TestCBOR_OnlyDrawingsTravelAsBytes(t *testing.T) {
+	lookalike := pngDataURL + base64.StdEncoding.EncodeToString([]byte("not a drawing"))
+	msg := game.OutgoingMessage{Type: game.MsgTurnStart, Data: game.TurnStartData{
+		TurnType: game.TurnDraw, Prompt: lookalike,
+	}}
+	data, err := EncodeMessage(CodecCBOR, msg)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var turn struct {
+		Data struct{ Prompt interface{} }
+	}
+	if err := cborDec.Unmarshal(data, &turn); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if turn.Data.Prompt != lookalike {
+		t.Errorf("draw turn prompt = %#v, want the text", turn.Data.Prompt)
+	}
+
+	chain := &game.Chain{Entries: []game.ChainEntry{{Type: game.TurnGuess, Guess: lookalike}}}
+	data, err = EncodeMessage(CodecCBOR, game.OutgoingMessage{Type: game.MsgGameOver, Data: game.GameOverData{
+		Chains: []*game.Chain{chain},
+	}})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	var over struct {
+		Data struct {
+			Chains []struct {
+				Entries []struct{ Guess interface{} }
+			}
+		}
+	}
+	if err := cborDec.Unmarshal(data, &over); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if g := over.Data.Chains[0].Entries[0].Guess; g != lookalike {
+		t.Errorf("guess = %#v, want the text", g)
+	}
+
+	in, _ := cbor.Marshal(map[string]interface{}{
+		"type": game.MsgSubmitGuess,
+		"data": map[string]interface{}{"guess": []byte("cat")},
+	})
+	m, err := DecodeMessage(CodecCBOR, in)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Contains(string(m.Data), pngDataURL) {
+		t.Errorf("guess bytes decoded as a drawing: %s", m.Data)
+	}
+}
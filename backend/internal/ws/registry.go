@@ -0,0 +1,159 @@
+package ws
+
+import (
+	"drawl/internal/game"
+	"log"
+	"sync"
+
+	"nhooyr.io/websocket"
+)
+
+// SessionPolicy decides what happens when a player opens another
+// connection with the same token, e.g. in a second tab.
+type SessionPolicy int
+
+const (
+	// LatestWins closes the player's older connection when a new one
+	// registers, so only the newest stays live. The default.
+	LatestWins SessionPolicy = iota
+	// Mirror keeps every connection open and sends each of them the
+	// player's messages, for playing across devices.
+	Mirror
+)
+
+// StatusReplaced is the close code for a connection that a newer one with
+// the same token replaced. Clients shouldn't reconnect on it.
+const StatusReplaced websocket.StatusCode = 4000
+
+// ClientRegistry tracks all connected clients, indexed by player and by
+// game so a broadcast only touches the game's own connections. A player is
+// connected for as long as at least one of their connections is registered.
+type ClientRegistry struct {
+	Policy SessionPolicy
+
+	mu      sync.RWMutex
+	nextID  uint64
+	clients map[string][]*Client          // playerID -> connections, oldest first
+	games   map[string]map[uint64]*Client // gameCode -> connection ID -> connection
+}
+
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{
+		clients: make(map[string][]*Client),
+		games:   make(map[string]map[uint64]*Client),
+	}
+}
+
+// Add registers a connection and gives it an ID. Under LatestWins the
+// player's existing connections are kicked; under Mirror the new one joins
+// them and shares their activity, so a message on any keeps the player
+// online.
+func (cr *ClientRegistry) Add(c *Client) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.nextID++
+	c.ID = cr.nextID
+
+	inGame := cr.games[c.GameCode]
+	if inGame == nil {
+		inGame = make(map[uint64]*Client)
+		cr.games[c.GameCode] = inGame
+	}
+	inGame[c.ID] = c
+
+	existing := cr.clients[c.PlayerID]
+	if cr.Policy == Mirror {
+		if len(existing) > 0 && existing[0].act != nil {
+			c.act = existing[0].act
+		}
+		cr.clients[c.PlayerID] = append(existing, c)
+		return
+	}
+	for _, old := range existing {
+		old.kick(StatusReplaced, "replaced by a newer connection")
+		cr.unindex(old)
+	}
+	cr.clients[c.PlayerID] = []*Client{c}
+}
+
+// Remove unregisters a connection, reporting whether it was the player's
+// last one. Removing a connection that was already replaced has no effect.
+func (cr *ClientRegistry) Remove(c *Client) (last bool) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	conns := cr.clients[c.PlayerID]
+	for i, cc := range conns {
+		if cc.ID != c.ID {
+			continue
+		}
+		cr.unindex(c)
+		if len(conns) == 1 {
+			delete(cr.clients, c.PlayerID)
+			return true
+		}
+		cr.clients[c.PlayerID] = append(conns[:i:i], conns[i+1:]...)
+		return false
+	}
+	return false
+}
+
+// unindex drops a connection from its game's index. Must be called with
+// cr.mu held.
+func (cr *ClientRegistry) unindex(c *Client) {
+	inGame := cr.games[c.GameCode]
+	delete(inGame, c.ID)
+	if len(inGame) == 0 {
+		delete(cr.games, c.GameCode)
+	}
+}
+
+// Get returns the player's newest connection, or nil if they have none.
+func (cr *ClientRegistry) Get(playerID string) *Client {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	conns := cr.clients[playerID]
+	if len(conns) == 0 {
+		return nil
+	}
+	return conns[len(conns)-1]
+}
+
+func (cr *ClientRegistry) SendTo(playerID string, msg game.OutgoingMessage) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	for _, c := range cr.clients[playerID] {
+		c.Send(msg)
+	}
+}
+
+// BroadcastToGame sends msg to every connection in the game, whichever
+// player it belongs to. The message is encoded once per codec and protocol
+// version in use and the same bytes queued on each connection; nil means
+// the version doesn't get the message.
+func (cr *ClientRegistry) BroadcastToGame(gameCode string, msg game.OutgoingMessage) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	type encoding struct {
+		codec   Codec
+		version int
+	}
+	encoded := make(map[encoding][]byte, 1)
+	for _, c := range cr.games[gameCode] {
+		key := encoding{c.Codec, c.Version}
+		data, ok := encoded[key]
+		if !ok {
+			if m, send := game.ForVersion(msg, c.Version); send {
+				var err error
+				data, err = EncodeMessage(c.Codec, m)
+				if err != nil {
+					log.Printf("encode %s for game %s: %v", msg.Type, gameCode, err)
+					return
+				}
+			}
+			encoded[key] = data
+		}
+		if data != nil {
+			c.sendEncoded(msg.Type, data)
+		}
+	}
+}
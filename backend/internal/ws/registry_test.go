@@ -0,0 +1,158 @@
+package ws
+
+import (
+	"context"
+	"drawl/internal/game"
+	"fmt"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+func TestRegistry_LatestWinsKicksOlderConnection(t *testing.T) {
+	registry := NewClientRegistry()
+	old, oldConn := dialClient(t)
+	registry.Add(old)
+	c, conn := dialClient(t)
+	registry.Add(c)
+
+	if old.ID == c.ID {
+		t.Fatalf("connections share ID %d", c.ID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := oldConn.Read(ctx); websocket.CloseStatus(err) != StatusReplaced {
+		t.Fatalf("older connection read %v, want close status %d", err, StatusReplaced)
+	}
+
+	// The replaced connection's cleanup mustn't take the new one with it.
+	if registry.Remove(old) {
+		t.Error("removing the replaced connection reported the player's last one")
+	}
+	registry.SendTo("p1", game.OutgoingMessage{Type: game.MsgPlayerJoined})
+	if _, _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("new connection read: %v", err)
+	}
+	if !registry.Remove(c) {
+		t.Error("removing the only connection should report the player's last one")
+	}
+	if registry.Get("p1") != nil {
+		t.Error("player still registered after their last connection left")
+	}
+}
+
+func TestRegistry_MirrorSendsToEveryConnection(t *testing.T) {
+	registry := NewClientRegistry()
+	registry.Policy = Mirror
+	a, aConn := dialClient(t)
+	a.act = &activity{playerID: "p1"}
+	registry.Add(a)
+	b, bConn := dialClient(t)
+	registry.Add(b)
+
+	if b.act != a.act {
+		t.Error("mirrored connections should share activity")
+	}
+	registry.BroadcastToGame("GAME1", game.OutgoingMessage{Type: game.MsgPlayerJoined})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for name, conn := range map[string]*websocket.Conn{"first": aConn, "second": bConn} {
+		if _, _, err := conn.Read(ctx); err != nil {
+			t.Errorf("%s connection read: %v", name, err)
+		}
+	}
+
+	if registry.Remove(a) {
+		t.Error("removing one of two connections reported the player's last one")
+	}
+	if registry.Get("p1") != b {
+		t.Error("remaining connection not registered")
+	}
+	if !registry.Remove(b) {
+		t.Error("removing the remaining connection should report the player's last one")
+	}
+}
+
+func TestRegistry_BroadcastStaysInGame(t *testing.T) {
+	registry := NewClientRegistry()
+	c, _ := dialClient(t)
+	registry.Add(c)
+	other := benchClient("p2", "GAME2")
+	registry.Add(other)
+
+	registry.BroadcastToGame("GAME2", game.OutgoingMessage{Type: game.MsgPlayerJoined})
+	if n := len(other.queue); n != 1 {
+		t.Errorf("GAME2 client has %d messages queued, want 1", n)
+	}
+	registry.Remove(other)
+	if _, ok := registry.games["GAME2"]; ok {
+		t.Error("empty game left in the index")
+	}
+	if len(registry.games["GAME1"]) != 1 {
+		t.Error("GAME1 connection dropped from the index")
+	}
+}
+
+// benchClient is a Client with no socket or writer, so sends just queue.
+func benchClient(playerID, gameCode string) *Client {
+	return &Client{
+		PlayerID: playerID,
+		GameCode: gameCode,
+		Version:  game.ProtocolVersion,
+		Codec:    CodecJSON,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// BenchmarkBroadcastToGame sends a tick to one game with a growing number
+// of other games connected. "scan" is the old approach: check every
+// client's game code and encode the message once per recipient.
+func BenchmarkBroadcastToGame(b *testing.B) {
+	const perGame = 6
+	tick := game.OutgoingMessage{Type: game.MsgTurnTick, Data: game.TickData{Remaining: 30}}
+	for _, games := range []int{10, 1000, 5000} {
+		registry := NewClientRegistry()
+		for g := 0; g < games; g++ {
+			code := fmt.Sprintf("G%05d", g)
+			for p := 0; p < perGame; p++ {
+				registry.Add(benchClient(fmt.Sprintf("%s-p%d", code, p), code))
+			}
+		}
+		b.Run(fmt.Sprintf("indexed/games=%d", games), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				registry.BroadcastToGame("G00000", tick)
+			}
+		})
+		b.Run(fmt.Sprintf("scan/games=%d", games), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				registry.mu.RLock()
+				for _, conns := range registry.clients {
+					for _, c := range conns {
+						if c.GameCode == "G00000" {
+							c.Send(tick)
+						}
+					}
+				}
+				registry.mu.RUnlock()
+			}
+		})
+	}
+}
+
+func TestRegistry_BroadcastAdaptsToVersion(t *testing.T) {
+	registry := NewClientRegistry()
+	v3, v4 := benchClient("p1", "GAME1"), benchClient("p2", "GAME1")
+	v3.Version, v4.Version = 3, 4
+	registry.Add(v3)
+	registry.Add(v4)
+
+	registry.BroadcastToGame("GAME1", game.OutgoingMessage{Type: game.MsgPresence, Data: game.PresenceData{PlayerID: "p1"}})
+	if len(v3.queue) != 0 {
+		t.Error("presence queued for a v3 client")
+	}
+	if len(v4.queue) != 1 {
+		t.Error("presence not queued for a v4 client")
+	}
+}
@@ -0,0 +1,198 @@
+package ws
+
+import (
+	"context"
+	"drawl/internal/game"
+	"drawl/internal/httputil"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+// Event stream transport, for networks that block WebSockets. The server's
+// messages arrive as Server-Sent Events on GET /api/games/{code}/events and
+// the client's are POSTed one at a time to /api/games/{code}/messages. Both
+// go through the same Client, registry and limits as a WebSocket, so the
+// game can't tell the difference.
+
+// sseTransport writes frames as events on an open text/event-stream
+// response.
+type sseTransport struct {
+	mu     sync.Mutex
+	w      http.ResponseWriter
+	rc     *http.ResponseController
+	closed bool // once set, w may already be gone
+
+	closeOnce sync.Once
+	ended     chan struct{} // closed once the close event is written
+}
+
+func (t *sseTransport) write(ctx context.Context, f frame) error {
+	return t.send(ctx, "data: "+string(f.data)+"\n\n")
+}
+
+// ping writes a comment, keeping proxies from timing out an idle stream.
+func (t *sseTransport) ping(ctx context.Context) error {
+	return t.send(ctx, ": ping\n\n")
+}
+
+// close tells the client why the stream is ending with a "close" event,
+// the counterpart of a WebSocket close frame, and stops any further writes.
+// Only the first call's reason is sent.
+func (t *sseTransport) close(code websocket.StatusCode, reason string) {
+	t.closeOnce.Do(func() {
+		defer close(t.ended)
+		data, _ := json.Marshal(struct {
+			Code   int    `json:"code"`
+			Reason string `json:"reason"`
+		}{int(code), reason})
+		ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+		defer cancel()
+		t.send(ctx, "event: close\ndata: "+string(data)+"\n\n")
+		t.finish()
+	})
+}
+
+// finish stops any further writes. The handler calls it before returning,
+// after which w must not be touched.
+func (t *sseTransport) finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+}
+
+func (t *sseTransport) send(ctx context.Context, event string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return errClientClosed
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		t.rc.SetWriteDeadline(deadline)
+	}
+	if _, err := io.WriteString(t.w, event); err != nil {
+		return err
+	}
+	return t.rc.Flush()
+}
+
+// ServeEvents streams a player's messages as Server-Sent Events. Like the
+// WebSocket it takes the player token (Authorization: Bearer <token>, or
+// ?token=, which is all EventSource can send) and the protocol version as
+// v. Messages are always JSON.
+func (h *Handler) ServeEvents(w http.ResponseWriter, r *http.Request) {
+	version, err := game.NegotiateVersion(r.URL.Query().Get("v"))
+	if err != nil {
+		httputil.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	code, g, player := h.authenticate(w, r)
+	if player == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // don't let nginx hold events back
+	w.WriteHeader(http.StatusOK)
+	out := &sseTransport{w: w, rc: http.NewResponseController(w), ended: make(chan struct{})}
+	if err := out.rc.Flush(); err != nil {
+		log.Printf("event stream for %s: %v", player.ID, err)
+		return
+	}
+	defer out.finish()
+
+	client := newClient(player.ID, code, out)
+	client.Version = version
+	client.act = newActivity(g, player.ID)
+	h.Registry.Add(client)
+	g.HandleReconnect(player.ID) // sends the initial game state
+	defer h.leave(g, client)
+
+	// There are no pongs: a dead stream shows up as a failed write or the
+	// request's context ending.
+	t := time.NewTicker(h.Heartbeat.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client.done:
+			// Kicked or evicted: let the close event saying why go out
+			// before the response ends.
+			<-out.ended
+			return
+		case <-t.C:
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), h.Heartbeat.Timeout)
+		err := out.ping(ctx)
+		cancel()
+		if err != nil {
+			return
+		}
+		client.act.checkIdle(h.Heartbeat.IdleAfter)
+	}
+}
+
+// ServeMessages takes one message from a player, as the body of a POST in
+// the same { type, data } form a WebSocket frame has. The player needs an
+// open connection, normally the event stream, which is where any error
+// about the message is reported.
+func (h *Handler) ServeMessages(w http.ResponseWriter, r *http.Request) {
+	_, g, player := h.authenticate(w, r)
+	if player == nil {
+		return
+	}
+	client := h.Registry.Get(player.ID)
+	if client == nil {
+		httputil.Error(w, "not connected; open the event stream first", http.StatusConflict)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFrameBytes))
+	if err != nil {
+		httputil.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	codec := CodecJSON
+	if r.Header.Get("Content-Type") == "application/cbor" {
+		codec = CodecCBOR
+	}
+	if !client.receive(g, codec, data) {
+		log.Printf("closing %s: too many rejected messages", player.ID)
+		client.kick(websocket.StatusPolicyViolation, "too many rejected messages")
+		httputil.Error(w, "too many rejected messages", http.StatusTooManyRequests)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authenticate finds the game in the path and the player whose token the
+// request carries, writing an error response if either is missing. It
+// returns the game code as well, which unlike the game's state is safe to
+// read without its lock.
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) (string, *game.Game, *game.Player) {
+	token := httputil.PlayerToken(r)
+	if token == "" {
+		httputil.Error(w, "token required", http.StatusUnauthorized)
+		return "", nil, nil
+	}
+	code := strings.ToUpper(strings.TrimSpace(r.PathValue("code")))
+	g := h.Hub.GetGame(code)
+	if g == nil {
+		httputil.Error(w, "game not found", http.StatusNotFound)
+		return "", nil, nil
+	}
+	player := g.PlayerByToken(token)
+	if player == nil {
+		httputil.Error(w, "invalid token", http.StatusUnauthorized)
+		return "", nil, nil
+	}
+	return code, g, player
+}
@@ -0,0 +1,184 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"drawl/internal/game"
+	"drawl/internal/hub"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sseServer serves the event stream endpoints for a fresh game, returning
+// the server, the game and its host.
+func sseServer(t *testing.T) (*httptest.Server, *game.Game, *game.Player) {
+	t.Helper()
+	registry := NewClientRegistry()
+	h := NewHandler(hub.New(), registry)
+	host := game.NewHumanPlayer("Alice")
+	var code string
+	g := h.Hub.CreateGame(host,
+		func(playerID string, msg game.OutgoingMessage) { registry.SendTo(playerID, msg) },
+		func(msg game.OutgoingMessage) { registry.BroadcastToGame(code, msg) },
+		nil)
+	code = g.State.Code
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/games/{code}/events", h.ServeEvents)
+	mux.HandleFunc("POST /api/games/{code}/messages", h.ServeMessages)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, g, host
+}
+
+type sseEvent struct {
+	event string
+	data  string
+}
+
+// openEvents opens an event stream, passing on its events until it ends.
+func openEvents(t *testing.T, ctx context.Context, url string) <-chan sseEvent {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET events: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET events: status %d", resp.StatusCode)
+	}
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		var ev sseEvent
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			line := sc.Text()
+			switch {
+			case line == "":
+				if ev.data != "" {
+					events <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "event: "):
+				ev.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+// nextMessage returns the next message event of the given type.
+func nextMessage(t *testing.T, events <-chan sseEvent, msgType string) game.OutgoingMessage {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("stream ended waiting for %s", msgType)
+			}
+			var msg struct {
+				Type string          `json:"type"`
+				Seq  uint64          `json:"seq"`
+				Data json.RawMessage `json:"data"`
+			}
+			if ev.event != "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(ev.data), &msg); err != nil {
+				t.Fatalf("decode %q: %v", ev.data, err)
+			}
+			if msg.Type == msgType {
+				return game.OutgoingMessage{Type: msg.Type, Seq: msg.Seq, Data: msg.Data}
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", msgType)
+		}
+	}
+}
+
+func post(t *testing.T, url, token, body string) int {
+	t.Helper()
+	req, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestSSE_StreamsMessagesAndAcceptsPosts(t *testing.T) {
+	srv, g, host := sseServer(t)
+	base := srv.URL + "/api/games/" + g.State.Code
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if code := post(t, base+"/messages", host.Token, `{"type":"add_ai","data":{}}`); code != http.StatusConflict {
+		t.Errorf("POST without a stream: status %d, want %d", code, http.StatusConflict)
+	}
+
+	events := openEvents(t, ctx, base+"/events?token="+host.Token)
+	nextMessage(t, events, game.MsgGameState)
+
+	if code := post(t, base+"/messages", host.Token, `{"type":"add_ai","data":{}}`); code != http.StatusAccepted {
+		t.Fatalf("POST add_ai: status %d, want %d", code, http.StatusAccepted)
+	}
+	nextMessage(t, events, game.MsgPlayerJoined)
+
+	// Rejections come back on the stream, as they would on a WebSocket.
+	post(t, base+"/messages", host.Token, `{"type":"draw_faster"}`)
+	var e game.ErrorData
+	json.Unmarshal(nextMessage(t, events, game.MsgError).Data.(json.RawMessage), &e)
+	if e.Code != game.ErrCodeUnknownType {
+		t.Errorf("error code = %q, want %q", e.Code, game.ErrCodeUnknownType)
+	}
+
+	if code := post(t, base+"/messages", "nope", `{"type":"add_ai"}`); code != http.StatusUnauthorized {
+		t.Errorf("POST with a bad token: status %d, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestSSE_NewerStreamReplacesOlder(t *testing.T) {
+	srv, g, host := sseServer(t)
+	url := srv.URL + "/api/games/" + g.State.Code + "/events?token=" + host.Token
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	old := openEvents(t, ctx, url)
+	nextMessage(t, old, game.MsgGameState)
+	events := openEvents(t, ctx, url)
+	nextMessage(t, events, game.MsgGameState)
+
+	var closed *sseEvent
+	for ev := range old {
+		if ev.event == "close" {
+			closed = &ev
+		}
+	}
+	if closed == nil || !strings.Contains(closed.data, `"code":4000`) {
+		t.Fatalf("older stream ended with %+v, want a close event with code 4000", closed)
+	}
+
+	// The older stream has fully ended, cleanup included. Anything it told
+	// the game shows up on the newer stream ahead of the player joining.
+	post(t, srv.URL+"/api/games/"+g.State.Code+"/messages", host.Token, `{"type":"add_ai","data":{}}`)
+	for {
+		ev := <-events
+		if strings.Contains(ev.data, `"presence":"disconnected"`) || strings.Contains(ev.data, `"type":"player_left"`) {
+			t.Fatal("host disconnected although their newer stream is still open")
+		}
+		if strings.Contains(ev.data, `"type":"player_joined"`) {
+			break
+		}
+	}
+}
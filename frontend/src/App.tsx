@@ -5,7 +5,6 @@ import { Home } from './components/Home';
 import { Lobby } from './components/Lobby';
 import { GamePlay } from './components/GamePlay';
 import { Reveal } from './components/Reveal';
-import { AIError } from './components/AIError';
 import {
   MSG_ADD_AI, MSG_START_GAME, MSG_SUBMIT_DRAWING,
   MSG_SUBMIT_GUESS, MSG_KICK_PLAYER,
@@ -103,18 +102,12 @@ export default function App() {
           favDrawing={state.favDrawing}
           votingDone={state.votingDone}
           waiting={state.waiting}
-          onSubmitVotes={(successChains, favDrawing) => send(MSG_SUBMIT_VOTES, { successChains, favDrawing })}
+          voteErrors={state.voteErrors}
+          onSubmitVotes={(successChains, awards) => send(MSG_SUBMIT_VOTES, { successChains, awards })}
           onPlayAgain={() => send(MSG_PLAY_AGAIN)}
           onHome={handleGoHome}
         />
       )}
-
-      {state.screen === 'ai_error' && (
-        <AIError
-          message={state.aiError}
-          onRestart={handleGoHome}
-        />
-      )}
     </div>
   );
 }
@@ -8,11 +8,12 @@ interface Props {
   voted?: boolean;
   onVoteSuccess?: () => void;
   favouriteMode?: boolean;
+  playerId?: string; // their own drawings can't be picked
   favouriteKey?: string;
   onFavourite?: (entryKey: string) => void;
 }
 
-export function ChainCard({ chain, chainIndex, players, voteMode, voted, onVoteSuccess, favouriteMode, favouriteKey, onFavourite }: Props) {
+export function ChainCard({ chain, chainIndex, players, voteMode, voted, onVoteSuccess, favouriteMode, playerId, favouriteKey, onFavourite }: Props) {
   const getPlayerName = (id: string) => {
     return players.find(p => p.id === id)?.name || 'Unknown';
   };
@@ -38,7 +39,7 @@ export function ChainCard({ chain, chainIndex, players, voteMode, voted, onVoteS
       </div>
       <div className="chain-entries">
         {chain.entries.map((entry, i) => {
-          const entryKey = `${chainIndex}:${i}`;
+          const entryKey = entry.id;
           const isDrawing = entry.type === TURN_DRAW;
           const isFavSelected = favouriteKey === entryKey;
 
@@ -46,7 +47,7 @@ export function ChainCard({ chain, chainIndex, players, voteMode, voted, onVoteS
             <div key={i} className="chain-entry">
               <div className="chain-entry-header">
                 <span className="chain-player">{getPlayerName(entry.playerId)}</span>
-                {favouriteMode && isDrawing && entry.drawing && onFavourite && (
+                {favouriteMode && isDrawing && entry.drawing && entry.playerId !== playerId && onFavourite && (
                   <button
                     className={`btn-fav ${isFavSelected ? 'btn-fav-active' : ''}`}
                     onClick={() => onFavourite(entryKey)}
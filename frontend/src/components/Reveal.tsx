@@ -1,5 +1,5 @@
-import { useState } from 'react';
-import { Chain, Player } from '../lib/protocol';
+import { useEffect, useState } from 'react';
+import { AWARD_BEST_ART, Chain, EntryRef, Player, VoteError } from '../lib/protocol';
 import { ChainCard } from './ChainCard';
 import { Scoreboard } from './Scoreboard';
 
@@ -12,18 +12,24 @@ interface Props {
   favDrawing: string;
   votingDone: boolean;
   waiting: boolean;
-  onSubmitVotes: (successChains: number[], favDrawing: string) => void;
+  voteErrors: VoteError[];
+  onSubmitVotes: (successChains: number[], awards: Record<string, EntryRef>) => void;
   onPlayAgain: () => void;
   onHome: () => void;
 }
 
-export function Reveal({ chains, players, scores, playerId, hostId, favDrawing, votingDone, waiting, onSubmitVotes, onPlayAgain, onHome }: Props) {
+export function Reveal({ chains, players, scores, playerId, hostId, favDrawing, votingDone, waiting, voteErrors, onSubmitVotes, onPlayAgain, onHome }: Props) {
   const [selectedChains, setSelectedChains] = useState<Set<number>>(new Set());
   const [selectedFav, setSelectedFav] = useState('');
   const [submitted, setSubmitted] = useState(false);
   const isHost = playerId === hostId;
   const isWaiting = submitted || waiting;
 
+  // The server threw the vote out: let the player fix it and try again.
+  useEffect(() => {
+    if (voteErrors.length > 0) setSubmitted(false);
+  }, [voteErrors]);
+
   const toggleChain = (idx: number) => {
     setSelectedChains(prev => {
       const next = new Set(prev);
@@ -35,7 +41,12 @@ export function Reveal({ chains, players, scores, playerId, hostId, favDrawing,
 
   const handleSubmit = () => {
     setSubmitted(true);
-    onSubmitVotes(Array.from(selectedChains), selectedFav);
+    const awards: Record<string, EntryRef> = {};
+    const chainIdx = chains.findIndex(c => c.entries.some(e => e.id === selectedFav));
+    if (chainIdx >= 0) {
+      awards[AWARD_BEST_ART] = { chainIdx, entryId: selectedFav };
+    }
+    onSubmitVotes(Array.from(selectedChains), awards);
   };
 
   return (
@@ -62,12 +73,17 @@ export function Reveal({ chains, players, scores, playerId, hostId, favDrawing,
                 voted={selectedChains.has(i)}
                 onVoteSuccess={() => toggleChain(i)}
                 favouriteMode={!isWaiting && !votingDone}
+                playerId={playerId}
                 favouriteKey={selectedFav}
                 onFavourite={setSelectedFav}
               />
             ))}
           </div>
 
+          {!isWaiting && !votingDone && voteErrors.length > 0 && (
+            <div className="error-msg">{voteErrors.map(e => e.reason).join('; ')}</div>
+          )}
+
           {!isWaiting && !votingDone && (
             <button
               className="btn btn-primary btn-submit-votes"
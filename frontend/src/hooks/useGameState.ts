@@ -1,14 +1,14 @@
 import { useReducer } from 'react';
 import {
-  ServerMessage, GameStateData, TurnStartData, Player, Chain,
+  ServerMessage, GameStateData, TurnStartData, Player, Chain, VoteError,
   MSG_GAME_STATE, MSG_PLAYER_JOINED, MSG_PLAYER_LEFT, MSG_GAME_STARTED,
   MSG_TURN_START, MSG_TURN_TICK, MSG_WAITING, MSG_ROUND_COMPLETE,
-  MSG_GAME_OVER, MSG_AI_ERROR, MSG_ERROR,
+  MSG_GAME_OVER, MSG_ERROR,
   MSG_SCORE_UPDATE, MSG_RETURN_TO_LOBBY,
   PHASE_PLAYING, PHASE_REVEAL,
 } from '../lib/protocol';
 
-export type Screen = 'home' | 'lobby' | 'playing' | 'reveal' | 'ai_error';
+export type Screen = 'home' | 'lobby' | 'playing' | 'reveal';
 
 export interface GameState {
   screen: Screen;
@@ -28,7 +28,7 @@ export interface GameState {
   favDrawing: string;
   votingDone: boolean;
   error: string;
-  aiError: string;
+  voteErrors: VoteError[]; // why our last vote was rejected
 }
 
 const initialState: GameState = {
@@ -49,7 +49,7 @@ const initialState: GameState = {
   favDrawing: '',
   votingDone: false,
   error: '',
-  aiError: '',
+  voteErrors: [],
 };
 
 type Action =
@@ -126,12 +126,12 @@ function handleWSMessage(state: GameState, msg: ServerMessage): GameState {
     case MSG_ROUND_COMPLETE:
       return { ...state, round: msg.data.round };
     case MSG_GAME_OVER:
-      return { ...state, screen: 'reveal', chains: msg.data.chains, scores: msg.data.scores || state.scores, waiting: false, favDrawing: '', votingDone: false };
+      return { ...state, screen: 'reveal', chains: msg.data.chains, scores: msg.data.scores || state.scores, waiting: false, favDrawing: '', votingDone: false, voteErrors: [] };
     case MSG_SCORE_UPDATE:
       return {
         ...state,
         scores: msg.data.scores || state.scores,
-        favDrawing: msg.data.favDrawing || state.favDrawing,
+        favDrawing: msg.data.awards?.best_art?.entryId || state.favDrawing,
         votingDone: msg.data.votingDone || state.votingDone,
       };
     case MSG_RETURN_TO_LOBBY:
@@ -145,10 +145,10 @@ function handleWSMessage(state: GameState, msg: ServerMessage): GameState {
         chains: [],
         favDrawing: '',
         votingDone: false,
+        voteErrors: [],
       };
-    case MSG_AI_ERROR:
-      return { ...state, screen: 'ai_error', aiError: msg.data.message };
     case MSG_ERROR:
+      if (msg.data.errors) return { ...state, voteErrors: msg.data.errors };
       return { ...state, error: msg.data.message };
     default:
       return state;
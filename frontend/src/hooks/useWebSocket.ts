@@ -1,45 +1,101 @@
 import { useRef, useCallback, useEffect } from 'react';
-import { ServerMessage } from '../lib/protocol';
+import { MSG_GAME_STATE, MSG_RESYNC, PROTOCOL_VERSION, ServerMessage } from '../lib/protocol';
+
+// A live connection to the game, over a WebSocket or, where that's blocked,
+// an event stream plus POSTs.
+interface Transport {
+  send: (data: string) => void;
+  close: () => void;
+}
 
 export function useWebSocket(onMessage: (msg: ServerMessage) => void) {
-  const wsRef = useRef<WebSocket | null>(null);
+  const transportRef = useRef<Transport | null>(null);
   const onMessageRef = useRef(onMessage);
   onMessageRef.current = onMessage;
 
   const connect = useCallback((token: string, gameCode: string) => {
-    if (wsRef.current) {
-      wsRef.current.close();
-    }
-    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const host = window.location.host;
-    const ws = new WebSocket(`${protocol}//${host}/ws?token=${token}&game=${gameCode}`);
+    transportRef.current?.close();
 
-    ws.onmessage = (e) => {
-      const msg = JSON.parse(e.data) as ServerMessage;
+    // A broadcast that skips ahead means we missed one: ask for a fresh
+    // snapshot rather than trying to patch up the gap. Counting starts from
+    // the first game_state, which every connection gets; anything before
+    // it is already covered by that snapshot.
+    let lastSeq = 0;
+    let synced = false;
+    let resyncing = false;
+    const receive = (data: string) => {
+      const msg = JSON.parse(data) as ServerMessage;
+      if (msg.type === MSG_GAME_STATE) {
+        synced = true;
+        resyncing = false;
+        lastSeq = msg.seq;
+      } else if (synced && msg.seq > lastSeq + 1 && !resyncing) {
+        resyncing = true;
+        transportRef.current?.send(JSON.stringify({ type: MSG_RESYNC, data: {} }));
+      }
+      lastSeq = Math.max(lastSeq, msg.seq);
       onMessageRef.current(msg);
     };
 
-    ws.onclose = () => {
-      wsRef.current = null;
+    // Some networks block WebSockets; if ours never opens, fall back to
+    // Server-Sent Events for the server's messages and POSTs for ours.
+    const connectEvents = () => {
+      const base = `/api/games/${gameCode}`;
+      const es = new EventSource(`${base}/events?token=${token}&v=${PROTOCOL_VERSION}`);
+      es.onmessage = (e) => receive(e.data);
+      // The server ending the stream, e.g. because this player opened the
+      // game elsewhere; don't let EventSource reconnect.
+      es.addEventListener('close', () => {
+        es.close();
+        if (transportRef.current === transport) transportRef.current = null;
+      });
+      const transport: Transport = {
+        send: (data) => {
+          fetch(`${base}/messages`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', Authorization: `Bearer ${token}` },
+            body: data,
+          }).catch(() => {});
+        },
+        close: () => es.close(),
+      };
+      transportRef.current = transport;
     };
 
-    wsRef.current = ws;
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const host = window.location.host;
+    const ws = new WebSocket(`${protocol}//${host}/ws?token=${token}&game=${gameCode}&v=${PROTOCOL_VERSION}`);
+    let opened = false;
+    const transport: Transport = {
+      send: (data) => {
+        if (ws.readyState === WebSocket.OPEN) ws.send(data);
+      },
+      close: () => ws.close(),
+    };
+    ws.onopen = () => {
+      opened = true;
+    };
+    ws.onmessage = (e) => receive(e.data);
+    ws.onclose = () => {
+      if (transportRef.current !== transport) return;
+      transportRef.current = null;
+      if (!opened) connectEvents();
+    };
+    transportRef.current = transport;
   }, []);
 
   const send = useCallback((type: string, data?: any) => {
-    if (wsRef.current?.readyState === WebSocket.OPEN) {
-      wsRef.current.send(JSON.stringify({ type, data: data || {} }));
-    }
+    transportRef.current?.send(JSON.stringify({ type, data: data || {} }));
   }, []);
 
   const disconnect = useCallback(() => {
-    wsRef.current?.close();
-    wsRef.current = null;
+    transportRef.current?.close();
+    transportRef.current = null;
   }, []);
 
   useEffect(() => {
     return () => {
-      wsRef.current?.close();
+      transportRef.current?.close();
     };
   }, []);
 
@@ -1,3 +1,6 @@
+// Protocol version this client speaks; sent as `v` when connecting.
+export const PROTOCOL_VERSION = 5;
+
 // Client -> Server message types
 export const MSG_ADD_AI = 'add_ai';
 export const MSG_START_GAME = 'start_game';
@@ -5,7 +8,15 @@ export const MSG_SUBMIT_DRAWING = 'submit_drawing';
 export const MSG_SUBMIT_GUESS = 'submit_guess';
 export const MSG_KICK_PLAYER = 'kick_player';
 export const MSG_SUBMIT_VOTES = 'submit_votes';
+export const MSG_END_VOTING = 'end_voting';
 export const MSG_PLAY_AGAIN = 'play_again';
+export const MSG_UPDATE_SETTINGS = 'update_settings';
+export const MSG_ASSIGN_TEAM = 'assign_team';
+export const MSG_NUDGE = 'nudge';
+export const MSG_PAUSE_GAME = 'pause_game';
+export const MSG_RESUME_GAME = 'resume_game';
+export const MSG_ABORT_GAME = 'abort_game';
+export const MSG_RESYNC = 'resync';
 
 // Server -> Client message types
 export const MSG_GAME_STATE = 'game_state';
@@ -14,13 +25,23 @@ export const MSG_PLAYER_LEFT = 'player_left';
 export const MSG_GAME_STARTED = 'game_started';
 export const MSG_TURN_START = 'turn_start';
 export const MSG_TURN_TICK = 'turn_tick';
+export const MSG_VOTE_TICK = 'vote_tick';
 export const MSG_WAITING = 'waiting';
 export const MSG_ROUND_COMPLETE = 'round_complete';
 export const MSG_GAME_OVER = 'game_over';
-export const MSG_AI_ERROR = 'ai_error';
 export const MSG_ERROR = 'error';
 export const MSG_SCORE_UPDATE = 'score_update';
 export const MSG_RETURN_TO_LOBBY = 'return_to_lobby';
+export const MSG_SESSION_SUMMARY = 'session_summary';
+export const MSG_SETTINGS_UPDATED = 'settings_updated';
+export const MSG_TEAMS_UPDATED = 'teams_updated';
+export const MSG_HURRY_UP = 'hurry_up';
+export const MSG_GAME_PAUSED = 'game_paused';
+export const MSG_GAME_RESUMED = 'game_resumed';
+export const MSG_PLAYER_AWAY = 'player_away';
+export const MSG_SEAT_TAKEN_OVER = 'seat_taken_over';
+export const MSG_SEAT_RETURNED = 'seat_returned';
+export const MSG_PRESENCE = 'presence';
 
 export const TURN_DRAW = 0;
 export const TURN_GUESS = 1;
@@ -34,9 +55,12 @@ export interface Player {
   name: string;
   type: number; // 0 = human, 1 = AI
   index: number;
+  standInFor?: string; // set on bots holding a departed player's seat
+  presence?: 'online' | 'idle' | 'disconnected'; // humans only
 }
 
 export interface ChainEntry {
+  id: string;
   playerId: string;
   type: number;
   drawing?: string;
@@ -50,6 +74,7 @@ export interface Chain {
 }
 
 export interface GameStateData {
+  protocolVersion?: number;
   code: string;
   phase: number;
   players: Player[];
@@ -58,6 +83,19 @@ export interface GameStateData {
   hostId: string;
   playerId?: string;
   scores?: Record<string, number>;
+  // Progress through the current phase, enough to render it from scratch
+  remaining?: number;
+  turn?: TurnStartData;
+  submitted?: string[];
+  pending?: Record<string, number>;
+  chains?: Chain[];
+  voteTime?: number;
+  awardCategories?: string[];
+  voted?: string[];
+  vote?: { successChains: number[]; awards: Record<string, EntryRef> };
+  votingDone?: boolean;
+  awards?: Record<string, AwardWinner>;
+  winners?: string[];
 }
 
 export interface TurnStartData {
@@ -68,7 +106,42 @@ export interface TurnStartData {
   timeLimit: number;
 }
 
+export const AWARD_FUNNIEST = 'funniest';
+export const AWARD_BEST_ART = 'best_art';
+export const AWARD_LOST_IN_TRANSLATION = 'lost_in_translation';
+
+export interface EntryRef {
+  chainIdx: number;
+  entryId: string;
+}
+
+export interface AwardWinner extends EntryRef {
+  playerId: string;
+  votes: number;
+}
+
+// Codes on `error` messages the server sends when it rejects one of ours
+export const ERR_INVALID_MESSAGE = 'invalid_message';
+export const ERR_UNKNOWN_TYPE = 'unknown_type';
+export const ERR_TOO_LARGE = 'too_large';
+export const ERR_RATE_LIMITED = 'rate_limited';
+
+// A problem with one field of a rejected vote
+export interface VoteError {
+  field: string;
+  value: unknown;
+  reason: string;
+}
+
+export interface ErrorData {
+  message: string;
+  code?: string;
+  type?: string; // the rejected message's type
+  errors?: VoteError[]; // set when a vote is rejected
+}
+
 export interface ServerMessage {
   type: string;
+  seq: number; // broadcasts count up by one; direct messages and ticks repeat the last broadcast's
   data: any;
 }